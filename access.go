@@ -0,0 +1,133 @@
+package gomemfs
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// accessSlots is how many slots an AccessWindow is divided into; hits slide
+// out of the window one slot at a time.
+const accessSlots = 8
+
+// AccessWindow, if greater than zero, causes the hits reported by HotKeys to
+// be counted over a sliding window of that duration instead of since each key
+// was stored, so that keys that were popular once but no longer are not
+// reported as hot.
+type AccessWindow time.Duration
+
+func (fso AccessWindow) applyTo(fs *FS) error {
+	if fso < 0 {
+		return fmt.Errorf("invalid AccessWindow %v", time.Duration(fso))
+	}
+	fs.accessWindow = time.Duration(fso)
+	return nil
+}
+
+// KeyAccess describes how a key has been accessed, as returned by HotKeys and
+// ColdKeys.
+type KeyAccess struct {
+	Name string
+
+	// Hits is how many times the key was served from the FS, over the
+	// AccessWindow if one is set.
+	Hits uint64
+
+	// LastAccess is when the key was last served, or when it was stored if
+	// it has not been served since.
+	LastAccess time.Time
+}
+
+// HotKeys returns up to n of the keys with the most hits, most first, so that
+// operators can decide what to prefetch, pin or give a longer TTL. Keys with
+// no hits are not reported. Nothing is fulfilled.
+func (d *FS) HotKeys(n int) []KeyAccess {
+	d.mu.Lock()
+	defer d.unlock()
+	var ka []KeyAccess
+	for name := range d.keys.all() {
+		if k := d.lookup(name); k != nil {
+			if a := d.keyAccess(k); a.Hits > 0 {
+				ka = append(ka, a)
+			}
+		}
+	}
+	slices.SortFunc(ka, func(a, b KeyAccess) int {
+		if c := cmp.Compare(b.Hits, a.Hits); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return ka[:min(n, len(ka))]
+}
+
+// ColdKeys returns the keys that have not been served for at least idle,
+// least recently served first. Nothing is fulfilled.
+func (d *FS) ColdKeys(idle time.Duration) []KeyAccess {
+	d.mu.Lock()
+	defer d.unlock()
+	since := d.now().Add(-idle)
+	var ka []KeyAccess
+	for name := range d.keys.all() {
+		if k := d.lookup(name); k != nil {
+			if a := d.keyAccess(k); !a.LastAccess.After(since) {
+				ka = append(ka, a)
+			}
+		}
+	}
+	slices.SortFunc(ka, func(a, b KeyAccess) int {
+		if c := a.LastAccess.Compare(b.LastAccess); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return ka
+}
+
+// access records that k was served.
+func (d *FS) access(k *key) {
+	// must be called with fs.mu Locked
+	k.accessed = d.now()
+	if d.accessWindow <= 0 {
+		return
+	}
+	s := d.accessSlot(k.accessed)
+	if s-k.slot >= accessSlots {
+		k.slots = [accessSlots]uint32{}
+	} else {
+		for i := k.slot + 1; i <= s; i++ {
+			k.slots[i%accessSlots] = 0
+		}
+	}
+	k.slot = max(k.slot, s)
+	k.slots[s%accessSlots]++
+}
+
+// accessSlot returns the slot of the AccessWindow that t falls in.
+func (d *FS) accessSlot(t time.Time) int64 {
+	return t.UnixNano() / max(int64(d.accessWindow/accessSlots), 1)
+}
+
+// keyAccess describes how k has been accessed.
+func (d *FS) keyAccess(k *key) KeyAccess {
+	// must be called with fs.mu Locked
+	a := KeyAccess{Name: k.name, Hits: k.hits, LastAccess: k.accessed}
+	if a.LastAccess.IsZero() {
+		a.LastAccess = k.stored
+	}
+	if d.accessWindow <= 0 {
+		return a
+	}
+	a.Hits = 0
+	if k.accessed.IsZero() {
+		return a
+	}
+	// only the slots that are still within the window count
+	start := d.accessSlot(d.now()) - accessSlots
+	for i := max(k.slot-accessSlots, start) + 1; i <= k.slot; i++ {
+		a.Hits += uint64(k.slots[i%accessSlots])
+	}
+	return a
+}
@@ -0,0 +1,53 @@
+package gomemfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHotAndColdKeys(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		d.Put(name, []byte(name), now, nil)
+	}
+	for range 3 {
+		d.ReadFile("a")
+	}
+	now = now.Add(time.Hour)
+	d.ReadFile("b")
+
+	hot := d.HotKeys(5)
+	if len(hot) != 2 || hot[0].Name != "a" || hot[0].Hits != 3 || hot[1].Name != "b" {
+		t.Errorf("HotKeys = %+v", hot)
+	}
+	if hot := d.HotKeys(1); len(hot) != 1 || hot[0].Name != "a" {
+		t.Errorf("HotKeys(1) = %+v", hot)
+	}
+	cold := d.ColdKeys(30 * time.Minute)
+	if len(cold) != 2 || cold[0].Name != "a" || cold[1].Name != "c" {
+		t.Errorf("ColdKeys = %+v", cold)
+	}
+}
+
+func TestAccessWindow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(AccessWindow(time.Minute), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a", []byte("a"), now, nil)
+	d.Put("b", []byte("b"), now, nil)
+	for range 5 {
+		d.ReadFile("a")
+	}
+	now = now.Add(2 * time.Minute)
+	d.ReadFile("b")
+	hot := d.HotKeys(5)
+	if len(hot) != 1 || hot[0].Name != "b" || hot[0].Hits != 1 {
+		t.Errorf("HotKeys = %+v, want only b", hot)
+	}
+}
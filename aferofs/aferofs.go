@@ -0,0 +1,206 @@
+// Package aferofs adapts a gomemfs FS to the [afero.Fs] interface, for code
+// written against afero rather than io/fs.
+package aferofs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ironiridis/gomemfs"
+	"github.com/spf13/afero"
+)
+
+// An Fs is an afero.Fs backed by a gomemfs FS. Files opened for reading are
+// Files of the FS, fulfilled as needed. Files opened for writing collect
+// their content privately and put it into the FS when closed, with a modtime
+// of that moment, so other readers see either the old content or the new.
+//
+// Directories are implicit in an FS: they exist while they hold keys. So
+// Mkdir and MkdirAll succeed without effect, and a directory left empty by
+// Remove or Rename disappears. Chmod, Chown and Chtimes are not supported.
+type Fs struct {
+	d *gomemfs.FS
+}
+
+var _ afero.Fs = (*Fs)(nil)
+
+// New returns an Fs for d.
+func New(d *gomemfs.FS) *Fs {
+	return &Fs{d: d}
+}
+
+// Name implements afero.Fs.
+func (a *Fs) Name() string {
+	return "gomemfs"
+}
+
+// Open implements afero.Fs.
+func (a *Fs) Open(name string) (afero.File, error) {
+	f, err := a.d.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &file{f: f, name: name}, nil
+}
+
+// Create implements afero.Fs.
+func (a *Fs) Create(name string) (afero.File, error) {
+	return a.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+// OpenFile implements afero.Fs. Files opened for writing start with the
+// current content of the key unless os.O_TRUNC is given, and the permission
+// bits of perm are stored with the key if it is created.
+func (a *Fs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return a.Open(name)
+	}
+	w := &writer{d: a.d, name: name, flag: flag, perm: perm}
+	f, err := a.d.Open(name)
+	switch {
+	case err == nil:
+		defer f.Close()
+		if flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		// as with os.OpenFile, perm only applies to new files
+		w.perm = fi.Mode().Perm()
+		if flag&os.O_TRUNC == 0 {
+			if w.buf, err = io.ReadAll(f); err != nil {
+				return nil, err
+			}
+		}
+	case errors.Is(err, fs.ErrNotExist) && flag&os.O_CREATE != 0:
+	default:
+		return nil, err
+	}
+	return w, nil
+}
+
+// Stat implements afero.Fs. As with FS.Stat, a missing key is only fulfilled
+// if the FS was created with StatFulfills.
+func (a *Fs) Stat(name string) (os.FileInfo, error) {
+	return a.d.Stat(name)
+}
+
+// Mkdir implements afero.Fs. It fails only if name already exists.
+func (a *Fs) Mkdir(name string, perm os.FileMode) error {
+	if _, err := a.d.Stat(name); err == nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	return nil
+}
+
+// MkdirAll implements afero.Fs. It fails only if name is a key.
+func (a *Fs) MkdirAll(name string, perm os.FileMode) error {
+	if fi, err := a.d.Stat(name); err == nil && !fi.IsDir() {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+	}
+	return nil
+}
+
+// Remove implements afero.Fs.
+func (a *Fs) Remove(name string) error {
+	fi, err := a.d.Lstat(name)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+	}
+	return a.d.Expire(name)
+}
+
+// RemoveAll implements afero.Fs, removing every key at or beneath name at
+// once.
+func (a *Fs) RemoveAll(name string) error {
+	names, err := a.keys(name)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	return a.d.Batch(func(tx *gomemfs.Tx) error {
+		for _, n := range names {
+			if err := tx.Remove(n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rename implements afero.Fs. Renaming a directory renames every key
+// beneath it at once.
+func (a *Fs) Rename(oldname, newname string) error {
+	names, err := a.keys(oldname)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: fs.ErrNotExist}
+	}
+	from, to := clean(oldname), clean(newname)
+	return a.d.Batch(func(tx *gomemfs.Tx) error {
+		for _, n := range names {
+			if err := tx.Rename(n, to+strings.TrimPrefix(n, from)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// keys returns the names of the keys held at or beneath name.
+func (a *Fs) keys(name string) ([]string, error) {
+	var names []string
+	err := fs.WalkDir(a.d, clean(name), func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !de.IsDir() {
+			names = append(names, p)
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return names, err
+}
+
+// Chmod implements afero.Fs, and is not supported.
+func (a *Fs) Chmod(name string, mode os.FileMode) error {
+	return &fs.PathError{Op: "chmod", Path: name, Err: errors.ErrUnsupported}
+}
+
+// Chown implements afero.Fs, and is not supported.
+func (a *Fs) Chown(name string, uid, gid int) error {
+	return &fs.PathError{Op: "chown", Path: name, Err: errors.ErrUnsupported}
+}
+
+// Chtimes implements afero.Fs, and is not supported.
+func (a *Fs) Chtimes(name string, atime, mtime time.Time) error {
+	return &fs.PathError{Op: "chtimes", Path: name, Err: errors.ErrUnsupported}
+}
+
+// clean returns name as the FS would name it, for matching names returned by
+// fs.WalkDir.
+func clean(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return "."
+	}
+	return name
+}
@@ -0,0 +1,151 @@
+package aferofs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ironiridis/gomemfs"
+	"github.com/spf13/afero"
+)
+
+func newFs(t *testing.T) (*gomemfs.FS, *Fs) {
+	d, err := gomemfs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, New(d)
+}
+
+func TestWriteAndRead(t *testing.T) {
+	d, a := newFs(t)
+	if err := afero.WriteFile(a, "docs/a.txt", []byte("hello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := afero.ReadFile(a, "docs/a.txt"); err != nil || string(b) != "hello" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if fi, err := a.Stat("docs/a.txt"); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Errorf("Stat = %v, %v; want the permission bits", fi, err)
+	}
+
+	f, err := a.OpenFile("docs/a.txt", os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(", world")
+	if b, _ := d.ReadFile("docs/a.txt"); string(b) != "hello" {
+		t.Errorf("content = %q before Close, want the old content", b)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("second Close = %v, want fs.ErrClosed", err)
+	}
+	if b, _ := d.ReadFile("docs/a.txt"); string(b) != "hello, world" {
+		t.Errorf("content = %q after Close", b)
+	}
+	if fi, _ := a.Stat("docs/a.txt"); fi.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want it kept for an existing file", fi.Mode())
+	}
+
+	if _, err := a.OpenFile("docs/a.txt", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("OpenFile with O_EXCL = %v, want fs.ErrExist", err)
+	}
+	if _, err := a.OpenFile("docs", os.O_WRONLY, 0); !errors.Is(err, syscall.EISDIR) {
+		t.Errorf("OpenFile of a directory = %v, want EISDIR", err)
+	}
+	if _, err := a.OpenFile("new", os.O_WRONLY, 0); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenFile without O_CREATE = %v, want fs.ErrNotExist", err)
+	}
+
+	r, err := a.Open("docs/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if _, err := r.Write([]byte("x")); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Write to a file opened for reading = %v, want fs.ErrPermission", err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	d, a := newFs(t)
+	f, err := a.Create("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("abcdef")
+	f.Seek(1, io.SeekStart)
+	b := make([]byte, 2)
+	if n, err := f.Read(b); n != 2 || err != nil || string(b) != "bc" {
+		t.Errorf("Read = %d, %q, %v", n, b, err)
+	}
+	f.WriteAt([]byte("XY"), 8)
+	f.Truncate(9)
+	if fi, _ := f.Stat(); fi.Size() != 9 || fi.Name() != "w" {
+		t.Errorf("Stat = %d %q", fi.Size(), fi.Name())
+	}
+	f.Close()
+	if got, _ := d.ReadFile("w"); string(got) != "abcdef\x00\x00X" {
+		t.Errorf("content = %q", got)
+	}
+
+	wo, _ := a.OpenFile("w", os.O_WRONLY, 0)
+	if _, err := wo.Read(b); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Read from O_WRONLY = %v, want fs.ErrPermission", err)
+	}
+	wo.Close()
+}
+
+func TestDirectories(t *testing.T) {
+	d, a := newFs(t)
+	now := time.Now()
+	d.Put("src/a.go", []byte("a"), now, nil)
+	d.Put("src/sub/b.go", []byte("b"), now, nil)
+	d.Put("keep", []byte("k"), now, nil)
+
+	if err := a.MkdirAll("new/dir", 0o755); err != nil {
+		t.Error(err)
+	}
+	if err := a.Mkdir("src", 0o755); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Mkdir of an existing directory = %v, want fs.ErrExist", err)
+	}
+	if err := a.MkdirAll("keep", 0o755); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("MkdirAll of a key = %v, want ENOTDIR", err)
+	}
+
+	names, err := afero.ReadDir(a, "src")
+	if err != nil || len(names) != 2 {
+		t.Errorf("ReadDir = %v, %v", names, err)
+	}
+	if err := a.Remove("src"); !errors.Is(err, syscall.ENOTEMPTY) {
+		t.Errorf("Remove of a directory = %v, want ENOTEMPTY", err)
+	}
+	if err := a.Rename("src", "lib"); err != nil {
+		t.Fatal(err)
+	}
+	if d.Exists("src/a.go") || !d.Exists("lib/a.go") || !d.Exists("lib/sub/b.go") {
+		t.Error("Rename did not move every key")
+	}
+	if err := a.Rename("missing", "x"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Rename of a missing name = %v, want fs.ErrNotExist", err)
+	}
+	if err := a.RemoveAll("lib"); err != nil {
+		t.Fatal(err)
+	}
+	if d.Len() != 1 {
+		t.Errorf("%d keys left after RemoveAll, want 1", d.Len())
+	}
+	if err := a.Remove("keep"); err != nil || d.Exists("keep") {
+		t.Errorf("Remove = %v", err)
+	}
+	if err := a.Chmod("keep", 0); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("Chmod = %v, want errors.ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,215 @@
+package aferofs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"time"
+
+	"github.com/ironiridis/gomemfs"
+)
+
+// A file is a key or directory opened for reading.
+type file struct {
+	f    fs.File
+	name string
+}
+
+func (f *file) Name() string               { return f.name }
+func (f *file) Close() error               { return f.f.Close() }
+func (f *file) Read(p []byte) (int, error) { return f.f.Read(p) }
+func (f *file) Stat() (os.FileInfo, error) { return f.f.Stat() }
+func (f *file) Sync() error                { return nil }
+
+func (f *file) ReadAt(p []byte, off int64) (int, error) {
+	if ra, ok := f.f.(io.ReaderAt); ok {
+		return ra.ReadAt(p, off)
+	}
+	return 0, f.err("read", errors.ErrUnsupported)
+}
+
+func (f *file) Seek(offset int64, whence int) (int64, error) {
+	if s, ok := f.f.(io.Seeker); ok {
+		return s.Seek(offset, whence)
+	}
+	return 0, f.err("seek", errors.ErrUnsupported)
+}
+
+func (f *file) Readdir(count int) ([]os.FileInfo, error) {
+	rd, ok := f.f.(fs.ReadDirFile)
+	if !ok {
+		return nil, f.err("readdir", errors.ErrUnsupported)
+	}
+	des, err := rd.ReadDir(count)
+	fis := make([]os.FileInfo, 0, len(des))
+	for _, de := range des {
+		fi, ierr := de.Info()
+		if ierr != nil {
+			return fis, ierr
+		}
+		fis = append(fis, fi)
+	}
+	return fis, err
+}
+
+func (f *file) Readdirnames(n int) ([]string, error) {
+	fis, err := f.Readdir(n)
+	names := make([]string, len(fis))
+	for i, fi := range fis {
+		names[i] = fi.Name()
+	}
+	return names, err
+}
+
+func (f *file) Write(p []byte) (int, error)              { return 0, f.err("write", fs.ErrPermission) }
+func (f *file) WriteAt(p []byte, off int64) (int, error) { return 0, f.err("write", fs.ErrPermission) }
+func (f *file) WriteString(s string) (int, error)        { return 0, f.err("write", fs.ErrPermission) }
+func (f *file) Truncate(size int64) error                { return f.err("truncate", fs.ErrPermission) }
+
+func (f *file) err(op string, err error) error {
+	return &fs.PathError{Op: op, Path: f.name, Err: err}
+}
+
+// A writer is a key opened for writing. Its content is put into the FS when
+// it is closed.
+type writer struct {
+	d      *gomemfs.FS
+	name   string
+	buf    []byte
+	off    int64
+	flag   int
+	perm   os.FileMode
+	closed bool
+}
+
+func (w *writer) Name() string { return w.name }
+
+func (w *writer) Close() error {
+	if w.closed {
+		return w.err("close", fs.ErrClosed)
+	}
+	w.closed = true
+	content := w.buf
+	if content == nil {
+		content = []byte{}
+	}
+	w.buf = nil
+	return w.d.Put(w.name, content, time.Now(), nil, gomemfs.Mode(w.perm.Perm()))
+}
+
+func (w *writer) Read(p []byte) (int, error) {
+	n, err := w.ReadAt(p, w.off)
+	w.off += int64(n)
+	return n, err
+}
+
+func (w *writer) ReadAt(p []byte, off int64) (int, error) {
+	if w.closed {
+		return 0, w.err("read", fs.ErrClosed)
+	}
+	if w.flag&os.O_RDWR == 0 {
+		return 0, w.err("read", fs.ErrPermission)
+	}
+	if off < 0 {
+		return 0, w.err("read", fs.ErrInvalid)
+	}
+	if off >= int64(len(w.buf)) {
+		return 0, io.EOF
+	}
+	n := copy(p, w.buf[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (w *writer) Seek(offset int64, whence int) (int64, error) {
+	if w.closed {
+		return 0, w.err("seek", fs.ErrClosed)
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += w.off
+	case io.SeekEnd:
+		offset += int64(len(w.buf))
+	}
+	if offset < 0 {
+		return 0, w.err("seek", fs.ErrInvalid)
+	}
+	w.off = offset
+	return offset, nil
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if w.flag&os.O_APPEND != 0 {
+		w.off = int64(len(w.buf))
+	}
+	n, err := w.WriteAt(p, w.off)
+	w.off += int64(n)
+	return n, err
+}
+
+func (w *writer) WriteAt(p []byte, off int64) (int, error) {
+	if w.closed {
+		return 0, w.err("write", fs.ErrClosed)
+	}
+	if off < 0 {
+		return 0, w.err("write", fs.ErrInvalid)
+	}
+	if end := off + int64(len(p)); end > int64(len(w.buf)) {
+		w.buf = append(w.buf, make([]byte, end-int64(len(w.buf)))...)
+	}
+	return copy(w.buf[off:], p), nil
+}
+
+func (w *writer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *writer) Truncate(size int64) error {
+	if w.closed {
+		return w.err("truncate", fs.ErrClosed)
+	}
+	if size < 0 {
+		return w.err("truncate", fs.ErrInvalid)
+	}
+	if size <= int64(len(w.buf)) {
+		w.buf = w.buf[:size:size]
+	} else {
+		w.buf = append(w.buf, make([]byte, size-int64(len(w.buf)))...)
+	}
+	return nil
+}
+
+func (w *writer) Stat() (os.FileInfo, error) {
+	return &writerInfo{w: w, modtime: time.Now()}, nil
+}
+
+func (w *writer) Sync() error { return nil }
+
+func (w *writer) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, w.err("readdir", errors.ErrUnsupported)
+}
+
+func (w *writer) Readdirnames(n int) ([]string, error) {
+	return nil, w.err("readdir", errors.ErrUnsupported)
+}
+
+func (w *writer) err(op string, err error) error {
+	return &fs.PathError{Op: op, Path: w.name, Err: err}
+}
+
+// writerInfo describes a key being written.
+type writerInfo struct {
+	w       *writer
+	modtime time.Time
+}
+
+func (fi *writerInfo) Name() string       { return path.Base(fi.w.name) }
+func (fi *writerInfo) Size() int64        { return int64(len(fi.w.buf)) }
+func (fi *writerInfo) Mode() fs.FileMode  { return fi.w.perm.Perm() }
+func (fi *writerInfo) ModTime() time.Time { return fi.modtime }
+func (fi *writerInfo) IsDir() bool        { return false }
+func (fi *writerInfo) Sys() any           { return nil }
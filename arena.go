@@ -0,0 +1,39 @@
+package gomemfs
+
+// ArenaSize, if greater than zero, causes the content of keys that are put or
+// fulfilled to be copied into shared slabs of that many bytes, rather than
+// each being held in its own buffer. For caches of millions of small keys,
+// this greatly reduces the number of objects the garbage collector has to
+// track. A slab is only freed once every key copied into it is gone, so
+// memory is reclaimed less promptly when keys are short-lived. Content
+// larger than a quarter of a slab keeps its own buffer.
+type ArenaSize int
+
+func (fso ArenaSize) applyTo(fs *FS) error {
+	fs.arena = nil
+	if fso > 0 {
+		fs.arena = &arena{size: int(fso)}
+	}
+	return nil
+}
+
+// An arena hands out space in slabs that are only ever appended to.
+type arena struct {
+	size int
+	slab []byte
+}
+
+// copy returns a copy of b in the current slab, starting a new slab if it
+// does not fit, or b itself if it is too large.
+func (a *arena) copy(b []byte) []byte {
+	if len(b) == 0 || len(b) > a.size/4 {
+		return b
+	}
+	if len(a.slab)+len(b) > cap(a.slab) {
+		a.slab = make([]byte, 0, a.size)
+	}
+	off := len(a.slab)
+	a.slab = append(a.slab, b...)
+	// cap the result, so that appending to it cannot overwrite its neighbors
+	return a.slab[off:len(a.slab):len(a.slab)]
+}
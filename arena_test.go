@@ -0,0 +1,35 @@
+package gomemfs
+
+import (
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestArenaSize(t *testing.T) {
+	d, err := New(ArenaSize(64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b, big := []byte("aaaa"), []byte("bbbb"), make([]byte, 32)
+	d.Put("a", a, time.Now(), nil)
+	d.Put("b", b, time.Now(), nil)
+	d.Put("big", big, time.Now(), nil)
+	a[0] = 'x'
+	if got, _ := d.ReadFile("a"); string(got) != "aaaa" {
+		t.Errorf("ReadFile = %q, want content copied into the arena", got)
+	}
+
+	d.mu.Lock()
+	ka, kb, kbig := d.keys.get("a"), d.keys.get("b"), d.keys.get("big")
+	d.unlock()
+	if unsafe.SliceData(kb.bytes) != (*byte)(unsafe.Add(unsafe.Pointer(unsafe.SliceData(ka.bytes)), 4)) {
+		t.Error("small keys not held in one slab")
+	}
+	if cap(ka.bytes) != 4 {
+		t.Errorf("cap = %d, want content capped so appends cannot reach its neighbor", cap(ka.bytes))
+	}
+	if unsafe.SliceData(kbig.bytes) != unsafe.SliceData(big) {
+		t.Error("content over a quarter of a slab was copied")
+	}
+}
@@ -0,0 +1,133 @@
+package gomemfs
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+)
+
+// Pop removes key name from the FS and returns its content and a FileInfo
+// describing it, under one acquisition of the lock, so that of several
+// concurrent callers exactly one receives the key. This suits queue-like and
+// one-time download uses, where ReadFile followed by Expire would race. Pop
+// never fulfills; if the key is not held, the error wraps [fs.ErrNotExist].
+// A symbolic link is removed itself, without following it.
+func (d *FS) Pop(name string) ([]byte, fs.FileInfo, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, nil, &fs.PathError{Op: "pop", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	if err := d.checkSealed("pop key"); err != nil {
+		return nil, nil, &fs.PathError{Op: "pop", Path: name, Err: err}
+	}
+	k := d.lookup(n)
+	if k == nil {
+		return nil, nil, &fs.PathError{Op: "pop", Path: name, Err: fs.ErrNotExist}
+	}
+	b, err := k.content()
+	if err != nil {
+		return nil, nil, err
+	}
+	if k.aead == nil && !k.wipe {
+		// the buffer may be wiped or unmapped once the key is retired
+		b = bytes.Clone(b)
+	}
+	d.keys.remove(d.fold(n))
+	d.retire(k)
+	d.emit(context.Background(), EventRemoved, k)
+	return b, k.fileStat(), nil
+}
+
+// Swap replaces the content of key name with content, stored with options o,
+// a modtime of now and no expiry beyond any DefaultTTL, and returns the
+// previous content, all under one acquisition of the lock. Callers can then
+// eg diff or archive the previous version without racing other writers. Swap
+// never fulfills: if the key is not held, nothing is stored and the error
+// wraps [fs.ErrNotExist].
+func (d *FS) Swap(name string, content []byte, o ...PutOption) ([]byte, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "swap", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	if err := d.checkSealed("swap key"); err != nil {
+		return nil, &fs.PathError{Op: "swap", Path: name, Err: err}
+	}
+	old := d.lookup(n)
+	if old == nil {
+		return nil, &fs.PathError{Op: "swap", Path: name, Err: fs.ErrNotExist}
+	}
+	prev, err := old.content()
+	if err != nil {
+		return nil, err
+	}
+	if old.aead == nil && !old.wipe {
+		prev = bytes.Clone(prev)
+	}
+	k := &key{bytes: content, name: n, fs: d, modtime: d.now(), expire: d.defaultExpire(nil)}
+	for _, opt := range o {
+		opt.applyToKey(k)
+	}
+	if err := d.store(k); err != nil {
+		return nil, &fs.PathError{Op: "swap", Path: name, Err: err}
+	}
+	return prev, nil
+}
+
+// Update replaces the content of key name with the result of calling fn with
+// its current content, or nil if the key is not held, storing it with options
+// o, a modtime of now and no expiry beyond any DefaultTTL. This allows safe
+// read-modify-write of a key, such as a counter or an appended manifest, by
+// concurrent goroutines. fn runs without the FS locked, so it may use the FS
+// and does not hold up other keys; if the key is changed by someone else
+// meanwhile, fn is called again with the new content, so it should have no
+// other side effects. It receives a copy that it may modify. If fn returns an
+// error, nothing is stored and Update returns that error. Update never
+// fulfills, but waits for a fulfillment of the key in progress to finish.
+func (d *FS) Update(name string, fn func(old []byte) ([]byte, error), o ...PutOption) error {
+	n, err := d.normalize(name)
+	if err != nil {
+		return &fs.PathError{Op: "update", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	for {
+		if err := d.checkSealed("update key"); err != nil {
+			return &fs.PathError{Op: "update", Path: name, Err: err}
+		}
+		if f := d.flights[d.fold(n)]; f != nil {
+			d.await(context.Background(), f)
+			continue
+		}
+		old := d.lookup(n)
+		var prev []byte
+		if old != nil {
+			if prev, err = old.content(); err != nil {
+				return err
+			}
+			if old.aead == nil && !old.wipe {
+				prev = bytes.Clone(prev)
+			}
+		}
+		var next []byte
+		d.unlocked(func() { next, err = fn(prev) })
+		if err != nil {
+			return err
+		}
+		if d.lookup(n) != old {
+			// changed while fn ran, so its result is stale
+			continue
+		}
+		k := &key{bytes: next, name: n, fs: d, modtime: d.now(), expire: d.defaultExpire(nil)}
+		for _, opt := range o {
+			opt.applyToKey(k)
+		}
+		if err := d.store(k); err != nil {
+			return &fs.PathError{Op: "update", Path: name, Err: err}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,107 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPop(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("job", []byte("work"), time.Now(), nil)
+	var got atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, fi, err := d.Pop("job")
+			if err == nil {
+				got.Add(1)
+				if string(b) != "work" || fi.Size() != 4 {
+					t.Errorf("Pop = %q, %v", b, fi)
+				}
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Pop = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got.Load() != 1 {
+		t.Errorf("%d callers popped the key, want 1", got.Load())
+	}
+	if d.Exists("job") {
+		t.Error("popped key still held")
+	}
+}
+
+func TestSwap(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Swap("cfg", []byte("v1")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Swap of a missing key = %v, want fs.ErrNotExist", err)
+	}
+	if d.Exists("cfg") {
+		t.Error("Swap stored a missing key")
+	}
+	d.Put("cfg", []byte("v1"), time.Now(), nil)
+	prev, err := d.Swap("cfg", []byte("v2"), Mode(0o600))
+	if err != nil || string(prev) != "v1" {
+		t.Errorf("Swap = %q, %v", prev, err)
+	}
+	if m, err := d.GetMeta("cfg"); err != nil || m.Mode != 0o600 {
+		t.Errorf("GetMeta = %+v, %v", m, err)
+	}
+	if b, err := d.ReadFile("cfg"); err != nil || string(b) != "v2" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	incr := func(old []byte) ([]byte, error) {
+		n, _ := strconv.Atoi(string(old))
+		return []byte(strconv.Itoa(n + 1)), nil
+	}
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.Update("counter", incr); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if b, err := d.ReadFile("counter"); err != nil || string(b) != "20" {
+		t.Errorf("counter = %q, %v; want 20", b, err)
+	}
+
+	errStop := errors.New("stop")
+	err = d.Update("counter", func(old []byte) ([]byte, error) {
+		// the FS is usable while fn runs
+		if _, err := d.ReadFile("counter"); err != nil {
+			t.Error(err)
+		}
+		return nil, errStop
+	})
+	if err != errStop {
+		t.Errorf("Update = %v, want the error from fn", err)
+	}
+	if b, _ := d.ReadFile("counter"); string(b) != "20" {
+		t.Errorf("counter = %q after a failed Update", b)
+	}
+}
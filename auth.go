@@ -0,0 +1,35 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+// An Authorizer is an FSOption that is consulted before an FS serves a key,
+// such as in Open, OpenContext, ReadFile, Stat, Versions and OpenVersion,
+// with the normalized name of the key and the context passed to OpenContext
+// (or context.Background).
+// If it returns an error, the key is neither served nor fulfilled, and the
+// caller receives an error wrapping [fs.ErrPermission] and the returned one.
+// It is called with the FS locked, so it must not call methods of the FS.
+// Directory listings are not authorized. A nil Authorizer allows everything.
+type Authorizer func(ctx context.Context, name string) error
+
+func (fso Authorizer) applyTo(fs *FS) error {
+	fs.authorize = fso
+	return nil
+}
+
+func (d *FS) authorized(ctx context.Context, name string) error {
+	// must be called with fs.mu Locked
+	if d.authorize == nil {
+		return nil
+	}
+	err := d.authorize(ctx, name)
+	if err == nil || errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", fs.ErrPermission, err)
+}
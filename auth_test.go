@@ -0,0 +1,47 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+type userKey struct{}
+
+func TestAuthorizer(t *testing.T) {
+	denied := errors.New("admins only")
+	d, err := New(Authorizer(func(ctx context.Context, name string) error {
+		if strings.HasPrefix(name, "admin/") && ctx.Value(userKey{}) != "root" {
+			return denied
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls++
+		return []byte(p), nil, nil, nil
+	})
+	d.Put("admin/keys", []byte("secret"), time.Now(), nil)
+	if _, err := d.ReadFile("admin/keys"); !errors.Is(err, fs.ErrPermission) || !errors.Is(err, denied) {
+		t.Errorf("ReadFile = %v, want fs.ErrPermission and the Authorizer's error", err)
+	}
+	if _, err := d.Stat("admin/keys"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Stat = %v, want fs.ErrPermission", err)
+	}
+	if _, err := d.ReadFile("admin/other"); !errors.Is(err, fs.ErrPermission) || calls != 0 {
+		t.Errorf("ReadFile = %v after %d calls, want not fulfilled", err, calls)
+	}
+	ctx := context.WithValue(context.Background(), userKey{}, "root")
+	if b, err := d.ReadFileContext(ctx, "admin/keys"); err != nil || string(b) != "secret" {
+		t.Errorf("ReadFileContext = %q, %v", b, err)
+	}
+	if _, err := d.ReadFile("public"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,182 @@
+package gomemfs
+
+import (
+	"context"
+	"io/fs"
+	"time"
+)
+
+// A Tx collects changes to an FS made within Batch.
+type Tx struct {
+	fs  *FS
+	ops []txOp
+}
+
+type txOp struct {
+	op   string // "put", "remove" or "rename"
+	name string // normalized; the new name for "rename"
+	from string // normalized old name for "rename"
+	k    *key   // for "put"
+}
+
+// Batch calls f with a Tx and, if f returns nil, applies the changes
+// collected by the Tx to the FS at once, in the order they were made, so that
+// readers never observe some of them without the others. If f or any change
+// fails, nothing is changed. Events for the changes are emitted after all
+// of them have been applied.
+func (d *FS) Batch(f func(tx *Tx) error) error {
+	tx := &Tx{fs: d}
+	if err := f(tx); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.unlock()
+	if err := d.checkSealed("apply batch"); err != nil {
+		return err
+	}
+
+	// the previous key under each changed name, to roll back on failure
+	undo := make(map[string]*key)
+	save := func(name string) {
+		f := d.fold(name)
+		if _, ok := undo[f]; !ok {
+			undo[f] = d.keys.get(f)
+		}
+	}
+	type event struct {
+		kind     EventKind
+		k        *key
+		replaced bool
+	}
+	var events []event
+	var retired []*key
+	for _, op := range tx.ops {
+		err := func() error {
+			switch op.op {
+			case "put":
+				old := d.lookup(op.name)
+				if err := d.checkFull(op.name); err != nil {
+					return &fs.PathError{Op: "put", Path: op.name, Err: err}
+				}
+				if err := d.checkQuota(op.name, int64(len(op.k.bytes))); err != nil {
+					return &fs.PathError{Op: "put", Path: op.name, Err: err}
+				}
+				k := op.k.clone(d)
+				if err := d.prepare(k); err != nil {
+					return &fs.PathError{Op: "put", Path: op.name, Err: err}
+				}
+				if old != nil && d.casePreserving {
+					k.name = old.name
+				}
+				k.stored = d.now()
+				save(op.name)
+				d.keys.set(d.fold(op.name), k)
+				retired = append(retired, old)
+				events = append(events, event{EventPut, k, old != nil})
+			case "remove":
+				if old := d.lookup(op.name); old != nil {
+					save(op.name)
+					d.keys.remove(d.fold(op.name))
+					retired = append(retired, old)
+					events = append(events, event{EventRemoved, old, false})
+				}
+			case "rename":
+				k := d.lookup(op.from)
+				if k == nil {
+					return &fs.PathError{Op: "rename", Path: op.from, Err: fs.ErrNotExist}
+				}
+				if op.from == op.name {
+					return nil
+				}
+				old := d.lookup(op.name)
+				if old == k {
+					// only the case of the name changes
+					old = nil
+				}
+				save(op.from)
+				d.keys.remove(d.fold(op.from))
+				if err := d.checkFull(op.name); err != nil {
+					return &fs.PathError{Op: "rename", Path: op.name, Err: err}
+				}
+				if err := d.checkQuota(op.name, k.size()); err != nil {
+					return &fs.PathError{Op: "rename", Path: op.name, Err: err}
+				}
+				c := k.clone(d)
+				c.name = op.name
+				c.stored = d.now()
+				save(op.name)
+				d.keys.set(d.fold(op.name), c)
+				retired = append(retired, k, old)
+				events = append(events, event{EventRemoved, k, false}, event{EventPut, c, old != nil})
+			}
+			return nil
+		}()
+		if err != nil {
+			for f, k := range undo {
+				if k == nil {
+					d.keys.remove(f)
+				} else {
+					d.keys.set(f, k)
+				}
+			}
+			return err
+		}
+	}
+	for _, k := range retired {
+		d.retire(k)
+	}
+	for _, e := range events {
+		d.emitEvent(context.Background(), e.kind, e.k, e.replaced)
+	}
+	return nil
+}
+
+// Put records putting a key, as by FS.Put.
+func (tx *Tx) Put(name string, content []byte, modtime time.Time, expire *time.Time, o ...PutOption) error {
+	n, err := tx.fs.normalize(name)
+	if err != nil {
+		return &fs.PathError{Op: "put", Path: name, Err: err}
+	}
+	if err := tx.fs.checkSize(n, content); err != nil {
+		return &fs.PathError{Op: "put", Path: name, Err: err}
+	}
+	k := &key{
+		bytes:   content,
+		name:    n,
+		modtime: modtime,
+		expire:  tx.fs.defaultExpire(expire),
+	}
+	for _, opt := range o {
+		opt.applyToKey(k)
+	}
+	tx.ops = append(tx.ops, txOp{op: "put", name: n, k: k})
+	return nil
+}
+
+// Remove records removing a key, as by FS.Expire. Removing a key that does
+// not exist is not an error.
+func (tx *Tx) Remove(name string) error {
+	n, err := tx.fs.normalize(name)
+	if err != nil {
+		return &fs.PathError{Op: "remove", Path: name, Err: err}
+	}
+	tx.ops = append(tx.ops, txOp{op: "remove", name: n})
+	return nil
+}
+
+// Rename records renaming key from to to, replacing any key called to. The
+// batch fails if from does not exist when the rename is applied. Renaming a key
+// to its own name changes nothing.
+func (tx *Tx) Rename(from, to string) error {
+	f, err := tx.fs.normalize(from)
+	if err != nil {
+		return &fs.PathError{Op: "rename", Path: from, Err: err}
+	}
+	t, err := tx.fs.normalize(to)
+	if err != nil {
+		return &fs.PathError{Op: "rename", Path: to, Err: err}
+	}
+	tx.ops = append(tx.ops, txOp{op: "rename", name: t, from: f})
+	return nil
+}
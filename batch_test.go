@@ -0,0 +1,82 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestBatch(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	d.Put("old.css", []byte("old"), now, nil)
+	d.Put("stale", []byte("x"), now, nil)
+	err = d.Batch(func(tx *Tx) error {
+		tx.Put("new.css", []byte("new"), now, nil)
+		tx.Rename("new.css", "app.css")
+		tx.Remove("stale")
+		return tx.Remove("missing")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("app.css"); err != nil || string(b) != "new" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if d.Exists("new.css") || d.Exists("stale") || !d.Exists("old.css") {
+		t.Error("Batch changes not applied")
+	}
+
+	err = d.Batch(func(tx *Tx) error {
+		tx.Put("app.css", []byte("broken"), now, nil)
+		tx.Remove("old.css")
+		return tx.Rename("gone", "elsewhere")
+	})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Batch = %v, want fs.ErrNotExist", err)
+	}
+	if b, _ := d.ReadFile("app.css"); string(b) != "new" || !d.Exists("old.css") {
+		t.Error("failed Batch was not rolled back")
+	}
+
+	abort := errors.New("abort")
+	if err := d.Batch(func(tx *Tx) error {
+		tx.Remove("app.css")
+		return abort
+	}); err != abort || !d.Exists("app.css") {
+		t.Errorf("Batch = %v, want abort and nothing changed", err)
+	}
+}
+
+func TestBatchRenameToSelf(t *testing.T) {
+	d, err := New(KeepVersions(2), WipeContent(true), CasePreserving(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a", []byte("v1"), time.Now(), nil)
+	d.Put("a", []byte("v2"), time.Now(), nil)
+	if err := d.Batch(func(tx *Tx) error { return tx.Rename("a", "a") }); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("a"); err != nil || string(b) != "v2" {
+		t.Errorf("ReadFile = %q, %v; want v2", b, err)
+	}
+	if vs, _ := d.Versions("a"); len(vs) != 1 {
+		t.Errorf("Versions = %+v, want only the put one", vs)
+	}
+
+	// changing only the case retires the key once
+	if err := d.Batch(func(tx *Tx) error { return tx.Rename("a", "A") }); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("A"); err != nil || string(b) != "v2" {
+		t.Errorf("ReadFile = %q, %v; want v2", b, err)
+	}
+	if vs, _ := d.Versions("a"); len(vs) != 2 {
+		t.Errorf("Versions = %+v, want one more", vs)
+	}
+}
@@ -0,0 +1,165 @@
+// Package billyfs adapts a gomemfs FS to the [billy.Filesystem] interface,
+// so that go-git and other billy consumers can read from and write to it.
+package billyfs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/ironiridis/gomemfs"
+	"github.com/ironiridis/gomemfs/aferofs"
+	"github.com/spf13/afero"
+)
+
+// A Filesystem is a billy.Filesystem backed by a gomemfs FS. Files are opened
+// and written as described for aferofs.Fs: written content is put into the
+// FS when the file is closed, and directories exist while they hold keys.
+// Locking is not supported, as a key being written is private to its file
+// until then.
+type Filesystem struct {
+	d    *gomemfs.FS
+	a    *aferofs.Fs
+	root string // "" for the root of the FS, else a directory without slashes at either end
+}
+
+var (
+	_ billy.Filesystem = (*Filesystem)(nil)
+	_ billy.Capable    = (*Filesystem)(nil)
+)
+
+// New returns a Filesystem for d.
+func New(d *gomemfs.FS) *Filesystem {
+	return &Filesystem{d: d, a: aferofs.New(d)}
+}
+
+// abs returns the name in the FS of name, relative to the root.
+func (b *Filesystem) abs(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if b.root == "" {
+		return name
+	}
+	return path.Join(b.root, name)
+}
+
+// Capabilities implements billy.Capable.
+func (b *Filesystem) Capabilities() billy.Capability {
+	return billy.DefaultCapabilities &^ billy.LockCapability
+}
+
+// Create implements billy.Basic.
+func (b *Filesystem) Create(name string) (billy.File, error) {
+	return b.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+// Open implements billy.Basic.
+func (b *Filesystem) Open(name string) (billy.File, error) {
+	return b.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile implements billy.Basic.
+func (b *Filesystem) OpenFile(name string, flag int, perm os.FileMode) (billy.File, error) {
+	f, err := b.a.OpenFile(b.abs(name), flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return &file{File: f, name: name}, nil
+}
+
+// Stat implements billy.Basic.
+func (b *Filesystem) Stat(name string) (os.FileInfo, error) {
+	return b.a.Stat(b.abs(name))
+}
+
+// Rename implements billy.Basic.
+func (b *Filesystem) Rename(from, to string) error {
+	return b.a.Rename(b.abs(from), b.abs(to))
+}
+
+// Remove implements billy.Basic.
+func (b *Filesystem) Remove(name string) error {
+	return b.a.Remove(b.abs(name))
+}
+
+// Join implements billy.Basic.
+func (b *Filesystem) Join(elem ...string) string {
+	return path.Join(elem...)
+}
+
+// TempFile implements billy.TempFile. The file is only held by the FS once it
+// is closed.
+func (b *Filesystem) TempFile(dir, prefix string) (billy.File, error) {
+	for {
+		var r [8]byte
+		rand.Read(r[:])
+		f, err := b.OpenFile(path.Join(dir, prefix+hex.EncodeToString(r[:])), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+		if !errors.Is(err, fs.ErrExist) {
+			return f, err
+		}
+	}
+}
+
+// ReadDir implements billy.Dir.
+func (b *Filesystem) ReadDir(name string) ([]os.FileInfo, error) {
+	return afero.ReadDir(b.a, b.abs(name))
+}
+
+// MkdirAll implements billy.Dir.
+func (b *Filesystem) MkdirAll(name string, perm os.FileMode) error {
+	return b.a.MkdirAll(b.abs(name), perm)
+}
+
+// Lstat implements billy.Symlink.
+func (b *Filesystem) Lstat(name string) (os.FileInfo, error) {
+	return b.d.Lstat(b.abs(name))
+}
+
+// Symlink implements billy.Symlink. A target starting with a slash is
+// resolved against the root of b.
+func (b *Filesystem) Symlink(target, link string) error {
+	if path.IsAbs(target) && b.root != "" {
+		target = "/" + path.Join(b.root, target)
+	}
+	return b.d.Symlink(target, b.abs(link))
+}
+
+// Readlink implements billy.Symlink.
+func (b *Filesystem) Readlink(link string) (string, error) {
+	target, err := b.d.ReadLink(b.abs(link))
+	if err != nil || b.root == "" || !path.IsAbs(target) {
+		return target, err
+	}
+	if rel, ok := strings.CutPrefix(target, "/"+b.root); ok && (rel == "" || rel[0] == '/') {
+		return "/" + strings.TrimPrefix(rel, "/"), nil
+	}
+	return target, nil
+}
+
+// Chroot implements billy.Chroot.
+func (b *Filesystem) Chroot(dir string) (billy.Filesystem, error) {
+	root := b.abs(dir)
+	if root == "." {
+		root = ""
+	}
+	return &Filesystem{d: b.d, a: b.a, root: root}, nil
+}
+
+// Root implements billy.Chroot.
+func (b *Filesystem) Root() string {
+	return "/" + b.root
+}
+
+// A file is an open afero.File, named as it was opened.
+type file struct {
+	afero.File
+	name string
+}
+
+func (f *file) Name() string  { return f.name }
+func (f *file) Lock() error   { return nil }
+func (f *file) Unlock() error { return nil }
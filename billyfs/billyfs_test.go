@@ -0,0 +1,79 @@
+package billyfs
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/ironiridis/gomemfs"
+)
+
+func TestFilesystem(t *testing.T) {
+	d, err := gomemfs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := New(d)
+	if b.Capabilities()&billy.LockCapability != 0 {
+		t.Error("Capabilities include locking")
+	}
+	repo, err := b.Chroot("repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Root() != "/repo" {
+		t.Errorf("Root = %q", repo.Root())
+	}
+	if err := util.WriteFile(repo, "/.git/HEAD", []byte("ref: main"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := d.ReadFile("repo/.git/HEAD"); string(got) != "ref: main" {
+		t.Errorf("content = %q, want it beneath the chroot", got)
+	}
+	f, err := repo.Open(".git/HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name() != ".git/HEAD" {
+		t.Errorf("Name = %q, want the name it was opened with", f.Name())
+	}
+	f.Close()
+
+	if err := repo.Rename(".git/HEAD", ".git/ORIG_HEAD"); err != nil || !d.Exists("repo/.git/ORIG_HEAD") {
+		t.Errorf("Rename = %v", err)
+	}
+	tmp, err := util.TempFile(repo, ".git", "pack-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp.Write([]byte("pack"))
+	tmp.Close()
+	if !strings.HasPrefix(tmp.Name(), ".git/pack-") {
+		t.Errorf("TempFile name = %q", tmp.Name())
+	}
+	fis, err := repo.ReadDir(".git")
+	if err != nil || len(fis) != 2 {
+		t.Errorf("ReadDir = %v, %v", fis, err)
+	}
+
+	if err := repo.Symlink("/.git/ORIG_HEAD", "head"); err != nil {
+		t.Fatal(err)
+	}
+	if target, _ := d.ReadLink("repo/head"); target != "/repo/.git/ORIG_HEAD" {
+		t.Errorf("stored target = %q, want it resolved against the chroot", target)
+	}
+	if target, err := repo.Readlink("head"); err != nil || target != "/.git/ORIG_HEAD" {
+		t.Errorf("Readlink = %q, %v", target, err)
+	}
+	if fi, err := repo.Lstat("head"); err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("Lstat = %v, %v", fi, err)
+	}
+	if got, err := util.ReadFile(repo, "head"); err != nil || string(got) != "ref: main" {
+		t.Errorf("ReadFile through the link = %q, %v", got, err)
+	}
+	if err := repo.Remove("head"); err != nil || d.Exists("repo/head") {
+		t.Errorf("Remove = %v", err)
+	}
+}
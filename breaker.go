@@ -0,0 +1,86 @@
+package gomemfs
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrCircuitOpen is recorded in a MissAttempt for a Fulfiller that was skipped
+// because its CircuitBreaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// A CircuitBreaker is an FSOption that stops an FS from calling a Fulfiller
+// that keeps failing. After Threshold consecutive errors from a Fulfiller,
+// it is skipped for CoolDown, during which keys are fulfilled by the other
+// Fulfillers (or not at all). The Fulfiller is then tried again; one more
+// error opens the breaker for another CoolDown, and anything else closes
+// it. Errors wrapping [fs.ErrNotExist] do not count as failures. The state of
+// each breaker is reported by FS.Stats. A CircuitBreaker with Threshold not
+// greater than zero disables this.
+type CircuitBreaker struct {
+	Threshold int
+	CoolDown  time.Duration
+}
+
+func (fso CircuitBreaker) applyTo(fs *FS) error {
+	fs.breaker = fso
+	return nil
+}
+
+// breakerState tracks the failures of one Fulfiller.
+type breakerState struct {
+	failures  int
+	openUntil time.Time
+}
+
+// BreakerStats describes the CircuitBreaker state of a Fulfiller.
+type BreakerStats struct {
+	// Fulfiller is the index of the Fulfiller in the order passed to
+	// FulfillWith.
+	Fulfiller     int
+	FulfillerName string
+
+	// Failures is the number of consecutive errors returned by the
+	// Fulfiller.
+	Failures int
+
+	// OpenUntil is the time until which the Fulfiller is skipped, or the
+	// zero time if it is not.
+	OpenUntil time.Time
+}
+
+// skip reports whether the Fulfiller at idx is to be skipped.
+func (d *FS) skip(idx int) bool {
+	// must be called with fs.mu Locked
+	if d.breaker.Threshold <= 0 {
+		return false
+	}
+	b := d.breakers[idx]
+	return b != nil && d.now().Before(b.openUntil)
+}
+
+// record updates the breaker of the Fulfiller at idx after it returned err.
+func (d *FS) record(idx int, err error) {
+	// must be called with fs.mu Locked
+	if d.breaker.Threshold <= 0 {
+		return
+	}
+	b := d.breakers[idx]
+	if err == nil {
+		if b != nil {
+			delete(d.breakers, idx)
+		}
+		return
+	}
+	if b == nil {
+		if d.breakers == nil {
+			d.breakers = make(map[int]*breakerState)
+		}
+		b = &breakerState{}
+		d.breakers[idx] = b
+	}
+	b.failures++
+	if b.failures >= d.breaker.Threshold {
+		b.openUntil = d.now().Add(d.breaker.CoolDown)
+	}
+}
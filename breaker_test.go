@@ -0,0 +1,59 @@
+package gomemfs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }), CircuitBreaker{Threshold: 2, CoolDown: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failing := errors.New("backend down")
+	calls := 0
+	d.FulfillWith(
+		func(p string) ([]byte, *time.Time, *time.Time, error) {
+			return []byte("good"), nil, nil, nil
+		},
+		func(p string) ([]byte, *time.Time, *time.Time, error) {
+			calls++
+			return nil, nil, nil, failing
+		},
+	)
+	for range 2 {
+		if _, err := d.ReadFile("a"); !errors.Is(err, failing) {
+			t.Fatalf("ReadFile = %v, want the Fulfiller's error", err)
+		}
+	}
+	if b, err := d.ReadFile("a"); err != nil || string(b) != "good" || calls != 2 {
+		t.Fatalf("ReadFile with the breaker open = %q, %v after %d calls", b, err, calls)
+	}
+	s := d.Stats()
+	if len(s.Breakers) != 1 || s.Breakers[0].Fulfiller != 1 || s.Breakers[0].Failures != 2 ||
+		!s.Breakers[0].OpenUntil.Equal(now.Add(time.Minute)) {
+		t.Errorf("Breakers = %+v", s.Breakers)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := d.ReadFile("a"); !errors.Is(err, failing) || calls != 3 {
+		t.Errorf("ReadFile after CoolDown = %v after %d calls", err, calls)
+	}
+	if _, err := d.ReadFile("a"); err != nil || calls != 3 {
+		t.Errorf("breaker did not reopen after one more error: %v after %d calls", err, calls)
+	}
+}
+
+func TestStats(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a", []byte("abc"), time.Now(), nil)
+	d.Put("b/c", []byte("de"), time.Now(), nil)
+	if s := d.Stats(); s.Keys != 2 || s.Bytes != 5 || s.Breakers != nil {
+		t.Errorf("Stats = %+v", s)
+	}
+}
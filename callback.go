@@ -0,0 +1,57 @@
+package gomemfs
+
+// OnExpire is an FSOption that registers a callback to be run with the name
+// and content of every key discarded because it has expired. The callback is
+// run after the FS has been unlocked, so it may perform slow work such as
+// writing the content back to durable storage, or call methods on the FS. The
+// content must not be modified.
+type OnExpire func(name string, content []byte)
+
+func (fso OnExpire) applyTo(fs *FS) error {
+	fs.onExpire = fso
+	return nil
+}
+
+// OnEvict is an FSOption that registers a callback to be run with the name
+// and content of every key discarded before it expired, whether by FS.Expire
+// or by the FS itself. It is run under the same conditions as OnExpire.
+type OnEvict func(name string, content []byte)
+
+func (fso OnEvict) applyTo(fs *FS) error {
+	fs.onEvict = fso
+	return nil
+}
+
+func (d *FS) later(f func()) {
+	// must be called with fs.mu Locked
+	d.deferred = append(d.deferred, f)
+}
+
+func (d *FS) unlock() {
+	// runs any callbacks queued while the FS was locked, in order, once the
+	// lock has been released, and then wipes content for WipeContent
+	p, w := d.deferred, d.wipes
+	d.deferred, d.wipes = nil, nil
+	d.mu.Unlock()
+	runDeferred(p, w)
+}
+
+// unlockAsync is unlock, except that the queued callbacks and wipes are run on
+// a new goroutine, for callers that must not wait for them.
+func (d *FS) unlockAsync() {
+	p, w := d.deferred, d.wipes
+	d.deferred, d.wipes = nil, nil
+	d.mu.Unlock()
+	if len(p) > 0 || len(w) > 0 {
+		go runDeferred(p, w)
+	}
+}
+
+func runDeferred(p []func(), w [][]byte) {
+	for _, f := range p {
+		f()
+	}
+	for _, b := range w {
+		clear(b)
+	}
+}
@@ -0,0 +1,42 @@
+package gomemfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOnExpireAndOnEvict(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var d *FS
+	expired := map[string]string{}
+	evicted := map[string]string{}
+	d, err := New(
+		Clock(func() time.Time { return now }),
+		OnExpire(func(name string, content []byte) {
+			// the FS is not locked while callbacks run
+			d.Exists(name)
+			expired[name] = string(content)
+		}),
+		OnEvict(func(name string, content []byte) {
+			d.Exists(name)
+			evicted[name] = string(content)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	soon := now.Add(time.Minute)
+	d.Put("old", []byte("old"), now, &soon)
+	d.Put("gone", []byte("gone"), now, nil)
+	d.Expire("gone")
+	now = now.Add(time.Hour)
+	if err := d.FlushExpired(); err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired["old"] != "old" {
+		t.Errorf("OnExpire saw %v", expired)
+	}
+	if len(evicted) != 1 || evicted["gone"] != "gone" {
+		t.Errorf("OnEvict saw %v", evicted)
+	}
+}
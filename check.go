@@ -0,0 +1,139 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Problems found by FS.Check that have no more specific error type.
+var (
+	errNilKey      = errors.New("nil key is held")
+	errMisfiled    = errors.New("key is held under the wrong name")
+	errForeignKey  = errors.New("key belongs to another FS")
+	errExpiredHeld = errors.New("expired key is still held")
+	errFileAndDir  = errors.New("key is also a directory")
+	errHistory     = errors.New("more previous versions are kept than KeepVersions")
+	errNotIndexed  = errors.New("key is missing from the index tree")
+	errStaleIndex  = errors.New("index tree holds a name that is not held")
+)
+
+// A CheckReport is the result of FS.Check.
+type CheckReport struct {
+	// Keys and Bytes are the number of keys held, other than expired ones,
+	// and the total size of their content.
+	Keys  int
+	Bytes int64
+
+	// Problems lists the inconsistencies found, sorted by name.
+	Problems []Problem
+}
+
+// A Problem is an inconsistency found by FS.Check in key Name, or in the FS
+// as a whole if Name is empty. Err is eg a *CorruptError, *TooLargeError or
+// *QuotaError where one applies.
+type Problem struct {
+	Name string
+	Err  error
+}
+
+// Check validates the internal state of the FS and reports any problems it
+// finds: nil or misfiled keys, keys missing from or left in the tree used to
+// list directories, expired keys that are still held, keys that
+// are also directories, content that is corrupt or too large, and limits
+// such as MaxKeys and quotas that are exceeded, eg because they were lowered
+// with Set. Nothing is changed or fulfilled. It reads every key, so it is
+// meant to be run after bulk imports or occasionally in long-running servers.
+func (d *FS) Check() CheckReport {
+	d.mu.Lock()
+	defer d.unlock()
+	var r CheckReport
+	problem := func(name string, err error) {
+		r.Problems = append(r.Problems, Problem{Name: name, Err: err})
+	}
+	now := d.now()
+	usage := make(map[string]*QuotaError)
+	live := make(map[string]bool, d.keys.len())
+	for n, k := range d.keys.all() {
+		switch {
+		case k == nil:
+			problem(n, errNilKey)
+			continue
+		case k.expire != nil && now.After(*k.expire):
+			problem(k.name, errExpiredHeld)
+			continue
+		}
+		r.Keys++
+		r.Bytes += k.size()
+		live[n] = true
+		if n != d.fold(k.name) {
+			problem(k.name, errMisfiled)
+		}
+		if !fs.ValidPath(k.name) || k.name == "." {
+			problem(k.name, fs.ErrInvalid)
+		}
+		if k.fs != d {
+			problem(k.name, errForeignKey)
+		}
+		if _, err := k.content(); err != nil {
+			problem(k.name, err)
+		}
+		if d.maxEntryBytes > 0 && k.size() > d.maxEntryBytes {
+			problem(k.name, &TooLargeError{Name: k.name, Size: k.size(), Limit: d.maxEntryBytes})
+		}
+		for _, q := range d.quotas {
+			dir, ok := d.quotaDir(q, k.name)
+			if !ok {
+				continue
+			}
+			u := usage[dir]
+			if u == nil {
+				u = &QuotaError{Quota: q, Dir: dir}
+				usage[dir] = u
+			}
+			u.Bytes += k.size()
+			u.Keys++
+		}
+	}
+	dirs := make(map[string]bool)
+	for n := range live {
+		// isDir is not used, as it removes expired keys
+		for dir := path.Dir(n); dir != "." && !dirs[dir]; dir = path.Dir(dir) {
+			dirs[dir] = true
+			if live[dir] {
+				problem(d.keys.get(dir).name, errFileAndDir)
+			}
+		}
+	}
+	if d.maxKeys > 0 && r.Keys > d.maxKeys {
+		problem("", ErrFull)
+	}
+	for _, dir := range slices.Sorted(maps.Keys(usage)) {
+		u := usage[dir]
+		if (u.Quota.MaxBytes > 0 && u.Bytes > u.Quota.MaxBytes) || (u.Quota.MaxKeys > 0 && u.Keys > u.Quota.MaxKeys) {
+			problem("", u)
+		}
+	}
+	indexed := make(map[string]bool, d.keys.len())
+	for n := range d.keys.tree() {
+		indexed[n] = true
+		if _, ok := d.keys.lookup(n); !ok {
+			problem(n, errStaleIndex)
+		}
+	}
+	for n := range d.keys.all() {
+		if !indexed[n] {
+			problem(n, errNotIndexed)
+		}
+	}
+	for n, h := range d.history {
+		if len(h) > max(d.keepVersions, 0) {
+			problem(n, errHistory)
+		}
+	}
+	slices.SortStableFunc(r.Problems, func(a, b Problem) int { return strings.Compare(a.Name, b.Name) })
+	return r
+}
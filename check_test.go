@@ -0,0 +1,74 @@
+package gomemfs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expire := now.Add(time.Minute)
+	d.Put("a", []byte("abc"), now, nil)
+	d.Put("b/c", []byte("de"), now, nil)
+	d.Put("short", []byte("f"), now, &expire)
+	if r := d.Check(); r.Keys != 3 || r.Bytes != 6 || r.Problems != nil {
+		t.Fatalf("Check = %+v", r)
+	}
+
+	now = now.Add(time.Hour)
+	d.Set(MaxKeys(1))
+	d.Set(MaxEntryBytes(2))
+	d.mu.Lock()
+	d.keys.set("misfiled", d.keys.get("a"))
+	d.unlock()
+	r := d.Check()
+	// the misfiled copy of a is a second key that is too large
+	var tle *TooLargeError
+	want := []struct {
+		name string
+		ok   func(error) bool
+	}{
+		{"", func(err error) bool { return errors.Is(err, ErrFull) }},
+		{"a", func(err error) bool { return errors.As(err, &tle) && tle.Limit == 2 }},
+		{"a", func(err error) bool { return errors.Is(err, errMisfiled) }},
+		{"short", func(err error) bool { return errors.Is(err, errExpiredHeld) }},
+	}
+	if len(r.Problems) != 5 {
+		t.Errorf("Problems = %+v", r.Problems)
+	}
+	for _, w := range want {
+		if !slices.ContainsFunc(r.Problems, func(p Problem) bool { return p.Name == w.name && w.ok(p.Err) }) {
+			t.Errorf("Problems = %+v, want one for %q", r.Problems, w.name)
+		}
+	}
+}
+
+func TestCheckIndex(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a/b", []byte("ab"), time.Now(), nil)
+	d.Put("c", []byte("c"), time.Now(), nil)
+	d.mu.Lock()
+	// a/b is left out of the tree, and the tree holds a name the map does not
+	delete(d.keys.root.children, "a")
+	d.keys.set("stale", nil)
+	delete(d.keys.m, "stale")
+	d.unlock()
+	r := d.Check()
+	for _, w := range []Problem{{"a/b", errNotIndexed}, {"stale", errStaleIndex}} {
+		if !slices.ContainsFunc(r.Problems, func(p Problem) bool { return p.Name == w.Name && errors.Is(p.Err, w.Err) }) {
+			t.Errorf("Problems = %+v, want %v for %q", r.Problems, w.Err, w.Name)
+		}
+	}
+	if len(r.Problems) != 2 {
+		t.Errorf("Problems = %+v", r.Problems)
+	}
+}
@@ -0,0 +1,37 @@
+package gomemfs
+
+import (
+	"slices"
+	"weak"
+)
+
+// Clone returns a new FS with the same options, Fulfillers and keys as d. The
+// clone is independent of d: keys can be put into or expired from either one
+// without affecting the other. Content is never modified in place, so both
+// share the underlying buffers until a key is replaced, and cloning is cheap
+// even for large filesystems, unless WipeContent is set, when content is
+// copied. Subscriptions are not carried over.
+func (d *FS) Clone() *FS {
+	d.mu.Lock()
+	defer d.unlock()
+	c := &FS{
+		callbacks: slices.Clone(d.callbacks),
+		options:   d.options,
+		tick:      d.tick,
+	}
+	if c.pressure > 0 {
+		c.sweeping = true
+		watchGC(weak.Make(c))
+	}
+	if c.arena != nil {
+		// slabs are appended to under the lock of one FS only
+		c.arena = &arena{size: c.arena.size}
+	}
+	for name, k := range d.keys.all() {
+		if k == nil {
+			continue
+		}
+		c.keys.set(name, k.clone(c))
+	}
+	return c
+}
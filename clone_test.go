@@ -0,0 +1,45 @@
+package gomemfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return []byte("fulfilled"), nil, nil, nil
+	})
+	d.Put("a", []byte("a"), time.Now(), nil)
+	d.Put("b", []byte("b"), time.Now(), nil)
+	c := d.Clone()
+
+	if &c.keys.get("a").bytes[0] != &d.keys.get("a").bytes[0] {
+		t.Error("clone copied content")
+	}
+	c.Put("a", []byte("changed"), time.Now(), nil)
+	c.Expire("b")
+	d.Put("new", []byte("new"), time.Now(), nil)
+	if b, _ := d.ReadFile("a"); string(b) != "a" || !d.Exists("b") {
+		t.Error("changes to the clone affected the original")
+	}
+	if c.Exists("new") {
+		t.Error("changes to the original affected the clone")
+	}
+	if b, err := c.ReadFile("x"); err != nil || string(b) != "fulfilled" {
+		t.Errorf("clone ReadFile = %q, %v; want its Fulfillers", b, err)
+	}
+
+	w, err := New(WipeContent(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Put("a", []byte("a"), time.Now(), nil)
+	wc := w.Clone()
+	if &wc.keys.get("a").bytes[0] == &w.keys.get("a").bytes[0] {
+		t.Error("clone shares content with WipeContent set")
+	}
+}
@@ -0,0 +1,118 @@
+// Command gomemfsd serves a gomemfs FS over HTTP, to try out the package or
+// serve a directory, zip archive or snapshot without writing a program.
+//
+// Usage:
+//
+//	gomemfsd [flags]
+//
+// The FS is filled from any combination of a snapshot written by FS.Save,
+// which is loaded at startup, and a directory or zip archive, which are
+// fulfilled from lazily as paths are requested:
+//
+//	gomemfsd -snapshot site.snap -dir ./public -ttl 1m
+//
+// Keys are served at the root of the server by gomemfs.FileServer, with
+// listings of directories that have no index file, and the inspector of
+// gomemfs.DebugHandler is served at the -debug path, which should not be
+// reachable by the public.
+package main
+
+import (
+	"archive/zip"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ironiridis/gomemfs"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	snapshot := flag.String("snapshot", "", "load keys from the snapshot `file`")
+	dir := flag.String("dir", "", "fulfill paths from files beneath `directory`")
+	zipFile := flag.String("zip", "", "fulfill paths from entries in the zip archive `file`")
+	ttl := flag.Duration("ttl", 0, "expire keys fulfilled from -dir or -zip after `duration` (0 for never)")
+	index := flag.String("index", "index.html", "comma-separated `names` of index files for directories")
+	debug := flag.String("debug", "/debug/gomemfs", "serve the inspector at `path` (empty to disable)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: gomemfsd [flags]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var idx []string
+	if *index != "" {
+		idx = strings.Split(*index, ",")
+	}
+	d, err := gomemfs.New(gomemfs.IndexFiles(idx...))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var exp *time.Duration
+	var opts []gomemfs.ComposeOption
+	if *ttl > 0 {
+		exp = ttl
+	} else {
+		// without a ttl, keep what is fulfilled until the process exits
+		opts = append(opts, gomemfs.TTLPolicy(func(string, fs.FileInfo) *time.Duration { return nil }))
+	}
+	if *zipFile != "" {
+		zr, err := zip.OpenReader(*zipFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer zr.Close()
+		f := gomemfs.ComposeZip(&zr.Reader, exp)
+		if exp == nil {
+			f = forever(f)
+		}
+		if err := d.FulfillWith(f); err != nil {
+			log.Fatal(err)
+		}
+	}
+	// Sources run in LIFO order, so files in the directory take precedence
+	if *dir != "" {
+		if err := d.FulfillFrom(gomemfs.ComposeSource(os.DirFS(*dir), exp, opts...)); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *snapshot != "" {
+		f, err := os.Open(*snapshot)
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = d.Load(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", gomemfs.FileServer(d, gomemfs.ListDirectories(nil)))
+	if *debug != "" {
+		mux.Handle(*debug, gomemfs.DebugHandler(d))
+		log.Printf("inspector at http://%s%s", *addr, *debug)
+	}
+	log.Printf("serving on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// forever makes f cache the content it returns without expiring.
+func forever(f gomemfs.Fulfiller) gomemfs.Fulfiller {
+	return func(name string) ([]byte, *time.Time, *time.Time, error) {
+		content, modtime, _, err := f(name)
+		e := gomemfs.Forever
+		return content, modtime, &e, err
+	}
+}
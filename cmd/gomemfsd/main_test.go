@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ironiridis/gomemfs"
+)
+
+func TestForever(t *testing.T) {
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := forever(func(name string) ([]byte, *time.Time, *time.Time, error) {
+		expire := mt.Add(time.Minute)
+		return []byte(name), &mt, &expire, nil
+	})
+	d, err := gomemfs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(f)
+	if b, err := d.ReadFile("a.txt"); err != nil || string(b) != "a.txt" {
+		t.Fatalf("ReadFile = %q, %v", b, err)
+	}
+	m, err := d.GetMeta("a.txt")
+	if err != nil || m.Expire != nil || !m.ModTime.Equal(mt) {
+		t.Errorf("GetMeta = %+v, %v; want cached without expiring", m, err)
+	}
+}
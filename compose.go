@@ -4,36 +4,212 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"path"
+	"strings"
 	"time"
 )
 
-// Compose adapts an existing fs.FS implementation to a Fulfiller. If ttl is
-// not nil, objects from t will be set to expire at time.Now().Add(ttl).
-func Compose(t fs.FS, ttl *time.Duration) Fulfiller {
-	return func(path string) ([]byte, *time.Time, *time.Time, error) {
-		f, err := t.Open(path)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("cannot open %q in composed %T: %w", path, t, err)
-		}
-		defer f.Close()
+// A ComposeOption changes the behavior of a Fulfiller created with Compose.
+type ComposeOption interface {
+	applyToCompose(*composer)
+}
+
+// MountPrefix causes a composed Fulfiller to serve only paths beneath the
+// given directory, with that directory removed before the path is looked up in
+// the source fs.FS. For example, with MountPrefix("static") a request for
+// "static/css/app.css" is served from "css/app.css". Other paths fall through
+// to other Fulfillers.
+type MountPrefix string
+
+func (o MountPrefix) applyToCompose(c *composer) {
+	c.prefix = strings.Trim(string(o), "/")
+}
+
+// Rewrite maps a path (after any MountPrefix has been removed) to the path
+// looked up in the source fs.FS of a composed Fulfiller. If it returns an
+// empty string, the path falls through to other Fulfillers.
+type Rewrite func(path string) string
+
+func (o Rewrite) applyToCompose(c *composer) {
+	c.rewrite = o
+}
+
+// Filter restricts a composed Fulfiller to the paths in its source fs.FS for
+// which it returns true. Other paths fall through to other Fulfillers.
+type Filter func(path string) bool
+
+func (o Filter) applyToCompose(c *composer) {
+	c.filters = append(c.filters, o)
+}
+
+// IncludeGlob restricts a composed Fulfiller to the paths in its source fs.FS
+// matching at least one of patterns. Patterns use the syntax of [path.Match].
+// A pattern without a slash is matched against each element of the path, so
+// "*.css" matches "css/app.css"; a pattern with a slash is matched against the
+// whole path and each of its parent directories, so "public/*" matches
+// "public/css/app.css". Invalid patterns never match.
+func IncludeGlob(patterns ...string) ComposeOption {
+	return Filter(func(p string) bool { return matchAny(patterns, p) })
+}
+
+// ExcludeGlob prevents a composed Fulfiller from serving paths in its source
+// fs.FS matching any of patterns, as described for IncludeGlob. For example
+// ExcludeGlob("*.map", ".*") excludes source maps and dotfiles.
+func ExcludeGlob(patterns ...string) ComposeOption {
+	return Filter(func(p string) bool { return !matchAny(patterns, p) })
+}
 
-		buf, err := io.ReadAll(f)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("cannot read %q in composed %T: %w", path, t, err)
+func matchAny(patterns []string, p string) bool {
+	for _, pat := range patterns {
+		if strings.Contains(pat, "/") {
+			for q := p; q != "." && q != "/"; q = path.Dir(q) {
+				if ok, _ := path.Match(pat, q); ok {
+					return true
+				}
+			}
+			continue
+		}
+		for _, elem := range strings.Split(p, "/") {
+			if ok, _ := path.Match(pat, elem); ok {
+				return true
+			}
 		}
+	}
+	return false
+}
+
+// TTLPolicy decides how long each file served by a composed Fulfiller is
+// cached, given its path in the source fs.FS and its FileInfo, overriding the
+// ttl passed to Compose. A nil result means the file is cached without
+// expiring, as if fulfilled with an expire of Forever. This allows eg
+// content-hashed assets to be kept forever while HTML is refreshed often, from
+// a single source.
+type TTLPolicy func(path string, info fs.FileInfo) *time.Duration
+
+func (o TTLPolicy) applyToCompose(c *composer) {
+	c.policy = o
+}
+
+type composer struct {
+	t       fs.FS
+	ttl     *time.Duration
+	policy  TTLPolicy
+	prefix  string
+	rewrite Rewrite
+	filters []Filter
+}
+
+// Compose adapts an existing fs.FS implementation to a Fulfiller. If ttl is
+// not nil, objects from t will be set to expire at time.Now().Add(ttl);
+// otherwise they are not cached, unless the FS has a DefaultTTL. A TTLPolicy
+// overrides ttl.
+func Compose(t fs.FS, ttl *time.Duration, opts ...ComposeOption) Fulfiller {
+	return ComposeSource(t, ttl, opts...).Fulfill
+}
+
+// ComposeSource is like Compose, but returns a StatSource to be added to an
+// FS with FulfillFrom. With StatFulfills set, FS.Stat then uses the Stat
+// method of t (if it implements [fs.StatFS]) to describe a path cheaply, and
+// its content is only read when it is opened.
+func ComposeSource(t fs.FS, ttl *time.Duration, opts ...ComposeOption) StatSource {
+	c := &composer{t: t, ttl: ttl}
+	for _, o := range opts {
+		o.applyToCompose(c)
+	}
+	return c
+}
 
-		var mt time.Time
-		if fi, err := f.Stat(); err != nil {
-			mt = time.Now()
-		} else {
-			mt = fi.ModTime()
+// Stat implements StatSource.
+func (c *composer) Stat(name string) (fs.FileInfo, error) {
+	src, ok := c.source(name)
+	if !ok {
+		return nil, nil
+	}
+	fi, err := fs.Stat(c.t, src)
+	if err != nil {
+		return nil, fmt.Errorf("cannot stat %q in composed %T: %w", src, c.t, err)
+	}
+	return fi, nil
+}
+
+// source maps a path requested from the FS to a path in c.t. If ok is false,
+// the path is not served by c.
+func (c *composer) source(name string) (src string, ok bool) {
+	src = name
+	if c.prefix != "" && c.prefix != "." {
+		rest, found := strings.CutPrefix(name, c.prefix+"/")
+		if !found {
+			return "", false
+		}
+		src = rest
+	}
+	if c.rewrite != nil {
+		src = c.rewrite(src)
+	}
+	if src == "" {
+		return "", false
+	}
+	for _, f := range c.filters {
+		if !f(src) {
+			return "", false
 		}
+	}
+	return src, true
+}
 
-		if ttl != nil {
-			expire := time.Now().Add(*ttl)
-			return buf, &mt, &expire, nil
+// Mode implements ModeSource, reporting the permission bits of name in the
+// source fs.FS.
+func (c *composer) Mode(name string) fs.FileMode {
+	src, ok := c.source(name)
+	if !ok {
+		return 0
+	}
+	fi, err := fs.Stat(c.t, src)
+	if err != nil {
+		return 0
+	}
+	return fi.Mode().Perm()
+}
+
+// Fulfill implements Source.
+func (c *composer) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	src, ok := c.source(name)
+	if !ok {
+		return nil, nil, nil, nil
+	}
+	f, err := c.t.Open(src)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot open %q in composed %T: %w", src, c.t, err)
+	}
+	defer f.Close()
+
+	var mt time.Time
+	ttl, policy := c.ttl, false
+	if fi, err := f.Stat(); err != nil {
+		mt = time.Now()
+	} else if fi.IsDir() {
+		// directories have no content; let the FS find them itself
+		return nil, nil, nil, nil
+	} else {
+		mt = fi.ModTime()
+		if c.policy != nil {
+			ttl, policy = c.policy(src, fi), true
 		}
+	}
+
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot read %q in composed %T: %w", src, c.t, err)
+	}
 
-		return buf, &mt, nil, nil
+	if ttl != nil {
+		expire := time.Now().Add(*ttl)
+		return buf, &mt, &expire, nil
+	}
+	if policy {
+		forever := Forever
+		return buf, &mt, &forever, nil
 	}
+
+	return buf, &mt, nil, nil
 }
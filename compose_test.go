@@ -0,0 +1,218 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// countingFS is a MapFS that counts how many times each file is opened.
+type countingFS struct {
+	fstest.MapFS
+	opens map[string]int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.opens[name]++
+	return c.MapFS.Open(name)
+}
+
+func newCountingFS() *countingFS {
+	return &countingFS{
+		MapFS: fstest.MapFS{
+			"app.css":    {Data: []byte("body{}")},
+			"index.html": {Data: []byte("<html>")},
+		},
+		opens: make(map[string]int),
+	}
+}
+
+func TestComposeWithoutTTLIsNotCached(t *testing.T) {
+	src := newCountingFS()
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(Compose(src, nil))
+	for range 2 {
+		if b, err := d.ReadFile("app.css"); err != nil || string(b) != "body{}" {
+			t.Fatalf("ReadFile = %q, %v", b, err)
+		}
+	}
+	if src.opens["app.css"] != 2 {
+		t.Errorf("opened %d times, want 2", src.opens["app.css"])
+	}
+	if d.Exists("app.css") {
+		t.Error("content with a nil expire was cached")
+	}
+}
+
+func TestComposeTTLPolicy(t *testing.T) {
+	src := newCountingFS()
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := time.Hour
+	d.FulfillWith(Compose(src, nil, TTLPolicy(func(p string, _ fs.FileInfo) *time.Duration {
+		if strings.HasSuffix(p, ".html") {
+			return &hour
+		}
+		return nil
+	})))
+	for range 2 {
+		d.ReadFile("app.css")
+		d.ReadFile("index.html")
+	}
+	if src.opens["app.css"] != 1 || src.opens["index.html"] != 1 {
+		t.Errorf("opens = %v, want each once", src.opens)
+	}
+	if m, err := d.GetMeta("app.css"); err != nil || m.Expire != nil {
+		t.Errorf("app.css expire = %v, %v; want nil", m.Expire, err)
+	}
+	if m, err := d.GetMeta("index.html"); err != nil || m.Expire == nil {
+		t.Errorf("index.html expire = %v, %v; want an hour", m.Expire, err)
+	}
+}
+
+func TestForeverAndDefaultTTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(DefaultTTL(time.Minute), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		if p == "nil" {
+			return []byte(p), nil, nil, nil
+		}
+		forever := Forever
+		return []byte(p), nil, &forever, nil
+	})
+	d.ReadFile("nil")
+	d.ReadFile("forever")
+	for _, name := range []string{"nil", "forever"} {
+		m, err := d.GetMeta(name)
+		if err != nil || m.Expire == nil || !m.Expire.Equal(now.Add(time.Minute)) {
+			t.Errorf("%s expire = %v, %v; want DefaultTTL", name, m.Expire, err)
+		}
+	}
+}
+
+func TestComposeFilters(t *testing.T) {
+	src := fstest.MapFS{
+		"css/app.css":     {Data: []byte("css")},
+		"css/app.css.map": {Data: []byte("map")},
+		"js/app.js":       {Data: []byte("js")},
+		"js/.secret":      {Data: []byte("secret")},
+		"vendor/x/y.js":   {Data: []byte("vendor")},
+	}
+	hour := time.Hour
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(Compose(src, &hour,
+		IncludeGlob("*.css", "*.js", "*.map", "*"),
+		ExcludeGlob("*.map", ".*", "vendor/x"),
+	))
+	for name, ok := range map[string]bool{
+		"css/app.css":     true,
+		"js/app.js":       true,
+		"css/app.css.map": false,
+		"js/.secret":      false,
+		"vendor/x/y.js":   false,
+	} {
+		if _, err := d.ReadFile(name); (err == nil) != ok {
+			t.Errorf("ReadFile(%q) = %v, want served %v", name, err, ok)
+		}
+	}
+
+	only, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	only.FulfillWith(Compose(src, &hour,
+		IncludeGlob("css/*"),
+		Filter(func(p string) bool { return !strings.HasSuffix(p, ".map") }),
+	))
+	if _, err := only.ReadFile("css/app.css"); err != nil {
+		t.Error(err)
+	}
+	if _, err := only.ReadFile("css/app.css.map"); err == nil {
+		t.Error("Filter did not exclude css/app.css.map")
+	}
+	if _, err := only.ReadFile("js/app.js"); err == nil {
+		t.Error("IncludeGlob(css/*) served js/app.js")
+	}
+}
+
+func TestComposeSourceStat(t *testing.T) {
+	src := newCountingFS()
+	d, err := New(StatFulfills(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := time.Hour
+	d.FulfillFrom(ComposeSource(src, &hour))
+	fi, err := d.Stat("app.css")
+	if err != nil || fi.Size() != int64(len("body{}")) {
+		t.Fatalf("Stat = %v, %v", fi, err)
+	}
+	if src.opens["app.css"] != 0 || d.Exists("app.css") {
+		t.Error("Stat read the content")
+	}
+	if _, err := d.Stat("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(missing) = %v, want fs.ErrNotExist", err)
+	}
+	if b, err := d.ReadFile("app.css"); err != nil || string(b) != "body{}" || src.opens["app.css"] != 1 {
+		t.Errorf("ReadFile = %q, %v after %d opens", b, err, src.opens["app.css"])
+	}
+}
+
+func TestComposeMountPrefixAndRewrite(t *testing.T) {
+	src := fstest.MapFS{
+		"css/app.css":  {Data: []byte("css")},
+		"spa.html":     {Data: []byte("spa")},
+		"static/x.css": {Data: []byte("nested")},
+	}
+	hour := time.Hour
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(Compose(src, &hour, MountPrefix("/static/")))
+	if b, err := d.ReadFile("static/css/app.css"); err != nil || string(b) != "css" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	for _, name := range []string{"css/app.css", "staticcss/app.css"} {
+		if _, err := d.ReadFile(name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("ReadFile(%q) outside the MountPrefix = %v, want fs.ErrNotExist", name, err)
+		}
+	}
+
+	spa, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spa.FulfillWith(Compose(src, &hour, MountPrefix("app"), Rewrite(func(p string) string {
+		switch {
+		case strings.HasPrefix(p, "css/"):
+			return p
+		case strings.HasPrefix(p, "api/"):
+			return ""
+		}
+		return "spa.html"
+	})))
+	if b, err := spa.ReadFile("app/users/42"); err != nil || string(b) != "spa" {
+		t.Errorf("ReadFile of a rewritten path = %q, %v", b, err)
+	}
+	if b, err := spa.ReadFile("app/css/app.css"); err != nil || string(b) != "css" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if _, err := spa.ReadFile("app/api/users"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile of a path rewritten to \"\" = %v, want fs.ErrNotExist", err)
+	}
+}
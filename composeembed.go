@@ -0,0 +1,114 @@
+package gomemfs
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"strings"
+	"time"
+)
+
+// hashLen is the number of hex digits of the digest put in hashed names.
+const hashLen = 8
+
+// An EmbedSource serves the files of an [embed.FS], each under its own name
+// and a hashed name that includes a digest of its content. It is created with
+// ComposeEmbed.
+type EmbedSource struct {
+	c      *composer
+	sums   map[string]string // path → hex SHA-256 of the content
+	hashed map[string]string // path → hashed name
+	plain  map[string]string // hashed name → path
+}
+
+// ComposeEmbed adapts an embed.FS to a StatSource, as ComposeSource does, and
+// computes the SHA-256 digest of every file it serves once, up front. Each
+// file can then also be fetched under a hashed name containing the start of
+// its digest before the extension, eg "css/app.3f2a1b9c.css" for
+// "css/app.css", as returned by Hashed. Since the content of a hashed name
+// can never change, it can be cached indefinitely by browsers and proxies,
+// which is the usual way to bust caches when assets are deployed.
+//
+// Embedded files never change, so they never expire, unless a TTLPolicy is
+// given; the other ComposeOptions apply as they do to Compose. A file is only
+// served under a hashed name if its own name would be served.
+func ComposeEmbed(e embed.FS, opts ...ComposeOption) (*EmbedSource, error) {
+	c := &composer{t: e}
+	for _, o := range opts {
+		o.applyToCompose(c)
+	}
+	es := &EmbedSource{
+		c:      c,
+		sums:   make(map[string]string),
+		hashed: make(map[string]string),
+		plain:  make(map[string]string),
+	}
+	err := fs.WalkDir(e, ".", func(src string, de fs.DirEntry, err error) error {
+		if err != nil || de.IsDir() {
+			return err
+		}
+		name := src
+		if c.prefix != "" && c.prefix != "." {
+			name = c.prefix + "/" + src
+		}
+		if s, ok := c.source(name); !ok || s != src {
+			return nil
+		}
+		buf, err := e.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(buf)
+		hs := hex.EncodeToString(sum[:])
+		ext := path.Ext(name)
+		h := strings.TrimSuffix(name, ext) + "." + hs[:hashLen] + ext
+		es.sums[name], es.hashed[name], es.plain[h] = hs, h, name
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot hash embedded files: %w", err)
+	}
+	return es, nil
+}
+
+// Hashed returns the hashed name of the file served as name, or name itself if
+// there is no such file.
+func (es *EmbedSource) Hashed(name string) string {
+	if h, ok := es.hashed[name]; ok {
+		return h
+	}
+	return name
+}
+
+// Manifest returns the hex-encoded SHA-256 digest of every file served, by
+// name.
+func (es *EmbedSource) Manifest() map[string]string {
+	return maps.Clone(es.sums)
+}
+
+// Fulfill implements Source.
+func (es *EmbedSource) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	forever := Forever
+	if p, ok := es.plain[name]; ok {
+		// the content of a hashed name cannot change, so it never expires
+		content, mt, _, err := es.c.Fulfill(p)
+		return content, mt, &forever, err
+	}
+	content, mt, expire, err := es.c.Fulfill(name)
+	if expire == nil {
+		expire = &forever
+	}
+	return content, mt, expire, err
+}
+
+// Stat implements StatSource.
+func (es *EmbedSource) Stat(name string) (fs.FileInfo, error) {
+	if p, ok := es.plain[name]; ok {
+		name = p
+	}
+	return es.c.Stat(name)
+}
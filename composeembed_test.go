@@ -0,0 +1,55 @@
+package gomemfs
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+)
+
+//go:embed testdata/embed
+var embedded embed.FS
+
+func TestComposeEmbed(t *testing.T) {
+	es, err := ComposeEmbed(embedded, Filter(func(p string) bool { return path.Ext(p) != ".js" }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const css = "testdata/embed/css/app.css"
+	sum := sha256.Sum256([]byte("body{}\n"))
+	digest := hex.EncodeToString(sum[:])
+	if m := es.Manifest(); len(m) != 1 || m[css] != digest {
+		t.Errorf("Manifest = %v", m)
+	}
+	hashed := es.Hashed(css)
+	if want := strings.TrimSuffix(css, ".css") + "." + digest[:8] + ".css"; hashed != want {
+		t.Errorf("Hashed = %q, want %q", hashed, want)
+	}
+	if h := es.Hashed("testdata/embed/js/app.js"); h != "testdata/embed/js/app.js" {
+		t.Errorf("Hashed of a filtered file = %q", h)
+	}
+
+	d, err := New(StatFulfills(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(es)
+	for _, name := range []string{css, hashed} {
+		if b, err := d.ReadFile(name); err != nil || string(b) != "body{}\n" {
+			t.Errorf("ReadFile(%q) = %q, %v", name, b, err)
+		}
+		if m, err := d.GetMeta(name); err != nil || m.Expire != nil {
+			t.Errorf("GetMeta(%q) = %+v, %v; want cached without expiring", name, m, err)
+		}
+	}
+	if _, err := d.ReadFile("testdata/embed/js/app.js"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile of a filtered file = %v, want fs.ErrNotExist", err)
+	}
+	if fi, err := es.Stat(hashed); err != nil || fi.Size() != int64(len("body{}\n")) {
+		t.Errorf("Stat = %v, %v", fi, err)
+	}
+}
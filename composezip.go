@@ -0,0 +1,54 @@
+package gomemfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ComposeZip adapts a zip archive to a Fulfiller. Only the requested entry is
+// decompressed, when it is first needed, and its modtime is taken from the
+// entry's header. Directory entries are ignored. If ttl is not nil, objects
+// from zr will be set to expire at time.Now().Add(ttl).
+//
+// To use a zip file on disk, open it with [zip.OpenReader] and keep it open
+// for as long as the Fulfiller is in use.
+func ComposeZip(zr *zip.Reader, ttl *time.Duration) Fulfiller {
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, zf := range zr.File {
+		if strings.HasSuffix(zf.Name, "/") {
+			continue
+		}
+		if _, dup := entries[zf.Name]; !dup {
+			entries[zf.Name] = zf
+		}
+	}
+
+	return func(path string) ([]byte, *time.Time, *time.Time, error) {
+		zf, ok := entries[path]
+		if !ok {
+			return nil, nil, nil, nil
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot open %q in zip: %w", path, err)
+		}
+		defer rc.Close()
+
+		var buf bytes.Buffer
+		buf.Grow(int(min(zf.UncompressedSize64, 1<<30)))
+		if _, err := io.Copy(&buf, rc); err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot read %q in zip: %w", path, err)
+		}
+
+		mt := zf.Modified
+		if ttl != nil {
+			expire := time.Now().Add(*ttl)
+			return buf.Bytes(), &mt, &expire, nil
+		}
+		return buf.Bytes(), &mt, nil, nil
+	}
+}
@@ -0,0 +1,51 @@
+package gomemfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestComposeZip(t *testing.T) {
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	zw.Create("dir/")
+	w, _ := zw.CreateHeader(&zip.FileHeader{Name: "dir/a.txt", Method: zip.Deflate, Modified: mt})
+	w.Write([]byte("a"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ttl := time.Hour
+	d.FulfillWith(ComposeZip(zr, &ttl))
+	if b, err := d.ReadFile("dir/a.txt"); err != nil || string(b) != "a" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	m, err := d.GetMeta("dir/a.txt")
+	if err != nil || !m.ModTime.Equal(mt) || m.Expire == nil {
+		t.Errorf("GetMeta = %+v, %v; want the entry's modtime and an expiry", m, err)
+	}
+	if _, err := d.ReadFile("missing"); err == nil {
+		t.Error("ReadFile of a missing entry succeeded")
+	}
+
+	u, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.FulfillWith(ComposeZip(zr, nil))
+	u.ReadFile("dir/a.txt")
+	if u.Exists("dir/a.txt") {
+		t.Error("entry cached without a ttl")
+	}
+}
@@ -0,0 +1,120 @@
+package gomemfs
+
+import (
+	"fmt"
+	"io/fs"
+	"sync"
+	"time"
+)
+
+// A CopyOption changes the behavior of FS.CopyAll.
+type CopyOption interface {
+	applyToCopy(*copyOptions)
+}
+
+type copyOptions struct {
+	ttl         time.Duration
+	concurrency int
+}
+
+// CopyTTL, if greater than zero, causes keys put by CopyAll to expire that long
+// after they were copied.
+type CopyTTL time.Duration
+
+func (o CopyTTL) applyToCopy(c *copyOptions) {
+	c.ttl = time.Duration(o)
+}
+
+// CopyConcurrency sets how many files CopyAll reads from its source at once.
+// The default is 1.
+type CopyConcurrency int
+
+func (o CopyConcurrency) applyToCopy(c *copyOptions) {
+	c.concurrency = int(o)
+}
+
+// CopyAll walks src and puts every regular file in it into the FS, with the
+// modtime reported by src. It is the eager counterpart of Compose, for when
+// loading everything up front is preferable to fulfilling on first use. If an
+// error occurs, CopyAll stops and returns it; files copied before the error
+// remain in the FS.
+func (d *FS) CopyAll(src fs.FS, opts ...CopyOption) error {
+	c := copyOptions{concurrency: 1}
+	for _, o := range opts {
+		o.applyToCopy(&c)
+	}
+	c.concurrency = max(c.concurrency, 1)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		done     = make(chan struct{})
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			close(done)
+		})
+	}
+
+	type job struct {
+		name string
+		de   fs.DirEntry
+	}
+	jobs := make(chan job)
+	for range c.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if err := d.copyOne(src, j.name, j.de, c.ttl); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	walkErr := fs.WalkDir(src, ".", func(name string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !de.Type().IsRegular() {
+			return nil
+		}
+		select {
+		case jobs <- job{name: name, de: de}:
+			return nil
+		case <-done:
+			return fs.SkipAll
+		}
+	})
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if walkErr != nil {
+		return fmt.Errorf("cannot walk %T: %w", src, walkErr)
+	}
+	return nil
+}
+
+func (d *FS) copyOne(src fs.FS, name string, de fs.DirEntry, ttl time.Duration) error {
+	content, err := fs.ReadFile(src, name)
+	if err != nil {
+		return fmt.Errorf("cannot read %q in %T: %w", name, src, err)
+	}
+	mt := d.now()
+	var mode Mode
+	if fi, err := de.Info(); err == nil {
+		mt, mode = fi.ModTime(), Mode(fi.Mode().Perm())
+	}
+	var expire *time.Time
+	if ttl > 0 {
+		e := d.now().Add(ttl)
+		expire = &e
+	}
+	return d.Put(name, content, mt, expire, mode)
+}
@@ -0,0 +1,41 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestCopyAll(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := fstest.MapFS{}
+	for i := range 50 {
+		src[fmt.Sprintf("d%d/f%d", i%5, i)] = &fstest.MapFile{Data: []byte{byte(i)}, ModTime: now, Mode: 0o640}
+	}
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.CopyAll(src, CopyConcurrency(4), CopyTTL(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	for name, f := range src {
+		m, err := d.GetMeta(name)
+		if err != nil || m.Mode != 0o640 || !m.ModTime.Equal(now) || m.Expire == nil || !m.Expire.Equal(now.Add(time.Hour)) {
+			t.Fatalf("%s: GetMeta = %+v, %v", name, m, err)
+		}
+		if b, _ := d.ReadFile(name); b[0] != f.Data[0] {
+			t.Fatalf("%s: content %v", name, b)
+		}
+	}
+
+	full, err := New(MaxKeys(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := full.CopyAll(src, CopyConcurrency(4)); !errors.Is(err, ErrFull) {
+		t.Errorf("CopyAll = %v, want ErrFull", err)
+	}
+}
@@ -0,0 +1,115 @@
+package gomemfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+)
+
+// A FileWriter is a writable handle to a key, returned by Create. Content is
+// collected in a private buffer and stored in the FS, as if by Put, when the
+// FileWriter is closed; until then the key is unchanged. The buffer becomes
+// the content of the key without being copied, so io.Copy into a FileWriter
+// streams straight into the key. A FileWriter must not be used concurrently.
+type FileWriter struct {
+	fs     *FS
+	name   string
+	o      []PutOption
+	buf    []byte
+	closed bool
+}
+
+// Create returns a FileWriter for key name. The key is stored with options o
+// and a modtime of the time it is closed, and never expires unless DefaultTTL
+// is set, as if put with a nil expire.
+func (d *FS) Create(name string, o ...PutOption) (*FileWriter, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	err = d.checkSealed("create key")
+	d.unlock()
+	if err != nil {
+		return nil, &fs.PathError{Op: "create", Path: name, Err: err}
+	}
+	return &FileWriter{fs: d, name: n, o: o}, nil
+}
+
+// Name returns the normalized name of the key being written.
+func (w *FileWriter) Name() string {
+	return w.name
+}
+
+// Write implements [io.Writer], appending p to the content.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	if w.closed {
+		return 0, fs.ErrClosed
+	}
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// WriteAt implements [io.WriterAt]. Writing past the end of the content
+// extends it, filling any gap with zeros. It does not affect where Write
+// appends.
+func (w *FileWriter) WriteAt(p []byte, off int64) (int, error) {
+	if w.closed {
+		return 0, fs.ErrClosed
+	}
+	if off < 0 {
+		return 0, &fs.PathError{Op: "writeat", Path: w.name, Err: fs.ErrInvalid}
+	}
+	if end := off + int64(len(p)); end > int64(len(w.buf)) {
+		w.buf = append(w.buf, make([]byte, end-int64(len(w.buf)))...)
+	}
+	return copy(w.buf[off:], p), nil
+}
+
+// ReadFrom implements [io.ReaderFrom], appending everything read from r to
+// the content without intermediate copies.
+func (w *FileWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.closed {
+		return 0, fs.ErrClosed
+	}
+	var total int64
+	for {
+		if len(w.buf) == cap(w.buf) {
+			w.buf = append(w.buf, 0)[:len(w.buf)]
+		}
+		n, err := r.Read(w.buf[len(w.buf):cap(w.buf)])
+		w.buf = w.buf[:len(w.buf)+n]
+		total += int64(n)
+		if errors.Is(err, io.EOF) {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Close stores the content in the FS. Closing a FileWriter more than once
+// returns [fs.ErrClosed].
+func (w *FileWriter) Close() error {
+	if w.closed {
+		return fs.ErrClosed
+	}
+	w.closed = true
+	content := w.buf
+	if content == nil {
+		content = []byte{}
+	}
+	w.buf = nil
+	// w.name is already normalized, and normalizing it again could change it
+	k := &key{
+		bytes:   content,
+		name:    w.name,
+		modtime: w.fs.now(),
+		expire:  w.fs.defaultExpire(nil),
+	}
+	for _, opt := range w.o {
+		opt.applyToKey(k)
+	}
+	return w.fs.putNamed(w.name, k)
+}
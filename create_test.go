@@ -0,0 +1,45 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(DefaultTTL(time.Hour), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := d.Create("dir/f.txt", Mode(0o600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("hello"))
+	w.WriteAt([]byte("J"), 0)
+	w.ReadFrom(strings.NewReader(" world"))
+	if d.Exists("dir/f.txt") {
+		t.Error("key stored before Close")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != fs.ErrClosed {
+		t.Errorf("second Close = %v, want fs.ErrClosed", err)
+	}
+	if b, err := d.ReadFile("dir/f.txt"); err != nil || string(b) != "Jello world" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	m, err := d.GetMeta("dir/f.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Mode != 0o600 || !m.ModTime.Equal(now) {
+		t.Errorf("mode, modtime = %v, %v", m.Mode, m.ModTime)
+	}
+	if m.Expire == nil || !m.Expire.Equal(now.Add(time.Hour)) {
+		t.Errorf("expire = %v, want DefaultTTL", m.Expire)
+	}
+}
@@ -0,0 +1,146 @@
+package gomemfs
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+type debugEntry struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	Expire  *time.Time
+	Hits    uint64
+}
+
+var debugTemplate = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html><head><title>gomemfs</title>
+<style>body{font-family:sans-serif}td,th{padding:0 1em;text-align:left}td.n{text-align:right}</style>
+</head><body>
+<h1>gomemfs: {{len .}} keys</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Modified</th><th>Expires</th><th>Hits</th><th></th></tr>
+{{range .}}<tr>
+<td><a href="?view={{.Name}}">{{.Name}}</a></td>
+<td class="n">{{.Size}}</td>
+<td>{{.ModTime.Format "2006-01-02 15:04:05 MST"}}</td>
+<td>{{if .Expire}}{{.Expire.Format "2006-01-02 15:04:05 MST"}}{{else}}never{{end}}</td>
+<td class="n">{{.Hits}}</td>
+<td><form method="post"><input type="hidden" name="expire" value="{{.Name}}"><button>Expire</button></form></td>
+</tr>
+{{end}}</table>
+</body></html>
+`))
+
+// DebugHandler returns an [http.Handler] that lists the keys currently held by
+// d along with their size, modtime, expiry and hit count. The content of a key
+// can be viewed with a "view" query parameter, and a key can be expired by
+// POSTing its name as the "expire" form value; such requests are refused if a
+// browser reports that they come from another origin, so that other sites
+// cannot make an operator's browser expire keys. The handler never causes
+// fulfillment. It is meant for operators and should not be exposed publicly.
+func DebugHandler(d *FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost:
+			if !sameOrigin(r) {
+				http.Error(w, "cross-origin request refused", http.StatusForbidden)
+				return
+			}
+			if err := d.Expire(r.FormValue("expire")); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+			return
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		if r.URL.Query().Has("view") {
+			d.debugView(w, r, r.URL.Query().Get("view"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		debugTemplate.Execute(w, d.debugEntries())
+	})
+}
+
+// sameOrigin reports whether r, which changes the FS, was not made from another
+// origin. Browsers send Sec-Fetch-Site, or at least Origin, with such requests;
+// clients that are not browsers send neither and are not at risk.
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "":
+	case "same-origin", "none":
+		return true
+	default:
+		return false
+	}
+	o := r.Header.Get("Origin")
+	if o == "" {
+		return true
+	}
+	u, err := url.Parse(o)
+	return err == nil && u.Host == r.Host
+}
+
+func (d *FS) debugView(w http.ResponseWriter, r *http.Request, name string) {
+	n, err := d.normalize(name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	d.mu.Lock()
+	k := d.lookup(n)
+	d.unlock()
+	if k == nil {
+		http.NotFound(w, r)
+		return
+	}
+	b, err := k.content()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// never let stored content be interpreted as markup on this page
+	ct := http.DetectContentType(b)
+	if strings.HasPrefix(ct, "text/") {
+		ct = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", ct)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(b)
+	// a mapped file stays mapped while k is reachable
+	runtime.KeepAlive(k)
+}
+
+func (d *FS) debugEntries() []debugEntry {
+	d.mu.Lock()
+	e := make([]debugEntry, 0, d.keys.len())
+	for _, k := range d.keys.all() {
+		// lookup removes the key if it has expired
+		if k == nil || d.lookup(k.name) == nil {
+			continue
+		}
+		e = append(e, debugEntry{
+			Name:    k.name,
+			Size:    k.size(),
+			ModTime: k.modtime,
+			Expire:  k.expire,
+			Hits:    k.hits,
+		})
+	}
+	d.unlock()
+	slices.SortFunc(e, func(a, b debugEntry) int { return strings.Compare(a.Name, b.Name) })
+	return e
+}
@@ -0,0 +1,94 @@
+package gomemfs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebugHandler(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("page.html", []byte("<script>alert(1)</script>"), time.Now(), nil)
+	d.Put("other", []byte("x"), time.Now(), nil)
+	h := DebugHandler(d)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/debug", nil))
+	if body := w.Body.String(); !strings.Contains(body, "2 keys") || !strings.Contains(body, "page.html") {
+		t.Errorf("listing = %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/debug?view=page.html", nil))
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if w.Body.String() != "<script>alert(1)</script>" {
+		t.Errorf("view = %q", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/debug?view=missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("view of a missing key = %d", w.Code)
+	}
+
+	r := httptest.NewRequest("POST", "/debug", strings.NewReader(url.Values{"expire": {"other"}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusSeeOther || d.Exists("other") {
+		t.Errorf("expire = %d, key held %v", w.Code, d.Exists("other"))
+	}
+
+	d.Put("other", []byte("x"), time.Now(), nil)
+	for _, header := range [][2]string{{"Origin", "https://evil.example"}, {"Sec-Fetch-Site", "cross-site"}} {
+		r := httptest.NewRequest("POST", "/debug", strings.NewReader(url.Values{"expire": {"other"}}.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.Header.Set(header[0], header[1])
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusForbidden || !d.Exists("other") {
+			t.Errorf("cross-origin expire with %s = %d, key held %v", header[0], w.Code, d.Exists("other"))
+		}
+	}
+	r = httptest.NewRequest("POST", "/debug", strings.NewReader(url.Values{"expire": {"other"}}.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Origin", "http://"+r.Host)
+	r.Header.Set("Sec-Fetch-Site", "same-origin")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusSeeOther || d.Exists("other") {
+		t.Errorf("same-origin expire = %d, key held %v", w.Code, d.Exists("other"))
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("DELETE", "/debug", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("DELETE = %d", w.Code)
+	}
+}
+
+func TestDebugHandlerExpired(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expire := now.Add(time.Minute)
+	d.Put("live", []byte("x"), now, nil)
+	d.Put("short", []byte("x"), now, &expire)
+	now = now.Add(time.Hour)
+
+	w := httptest.NewRecorder()
+	DebugHandler(d).ServeHTTP(w, httptest.NewRequest("GET", "/debug", nil))
+	if body := w.Body.String(); !strings.Contains(body, "1 keys") || strings.Contains(body, "short") {
+		t.Errorf("listing = %s", body)
+	}
+}
@@ -0,0 +1,199 @@
+package gomemfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	errIsDir  = errors.New("is a directory")
+	errNotDir = errors.New("not a directory")
+)
+
+// Directories in an FS are never stored. A directory exists as long as at
+// least one key currently held by the FS is beneath it, so keys that have not
+// been fulfilled yet do not show up in listings. The root directory "."
+// always exists.
+
+// ReadDir implements [fs.ReadDirFS]. It never causes fulfillment.
+func (d *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return d.readDirIn(".", name)
+}
+
+// readDirIn is ReadDir, resolving links within root.
+func (d *FS) readDirIn(root, name string) ([]fs.DirEntry, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return d.readDirNormalized(root, n, name)
+}
+
+// readDirNormalized is readDirIn for n, which has already been normalized from
+// name, eg by opening it, so that the Normalizers do not run on it twice.
+func (d *FS) readDirNormalized(root, n, name string) ([]fs.DirEntry, error) {
+	d.mu.Lock()
+	defer d.unlock()
+	n, err := d.followIn(root, n)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	es, _, ok := d.readDir(n)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	return es, nil
+}
+
+func (d *FS) isDir(name string) bool {
+	// must be called with fs.mu Locked
+	if name == "." {
+		return true
+	}
+	for n := range d.keys.under(d.fold(name)) {
+		if d.lookup(n) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *FS) readDir(name string) (entries []fs.DirEntry, modtime time.Time, ok bool) {
+	// must be called with fs.mu Locked
+	var prefix string
+	if name != "." {
+		prefix = name + "/"
+	}
+	files := make(map[string]*key)
+	dirs := make(map[string]time.Time)
+	folded := d.fold(prefix)
+	for n := range d.keys.under(folded) {
+		k := d.lookup(n)
+		if k == nil {
+			continue
+		}
+		if k.modtime.After(modtime) {
+			modtime = k.modtime
+		}
+		rest := n[len(prefix):]
+		if len(k.name) == len(n) {
+			// report the casing preserved by CasePreserving
+			rest = k.name[len(prefix):]
+		}
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			if mt, seen := dirs[rest[:i]]; !seen || k.modtime.After(mt) {
+				dirs[rest[:i]] = k.modtime
+			}
+		} else {
+			files[rest] = k
+		}
+	}
+	if len(files) == 0 && len(dirs) == 0 {
+		return nil, modtime, name == "."
+	}
+
+	entries = make([]fs.DirEntry, 0, len(files)+len(dirs))
+	for _, k := range files {
+		entries = append(entries, fs.FileInfoToDirEntry(k.fileStat()))
+	}
+	for n, mt := range dirs {
+		if _, shadowed := files[n]; shadowed {
+			// a key and a directory share a name; the key wins
+			continue
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(&dirInfo{name: prefix + n, modtime: mt}))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, modtime, true
+}
+
+// dirInfo describes a directory. Its modtime is that of the newest key
+// beneath it.
+type dirInfo struct {
+	name    string
+	modtime time.Time
+}
+
+func (s dirInfo) Name() string       { return path.Base(s.name) }
+func (s dirInfo) Size() int64        { return 0 }
+func (s dirInfo) Mode() fs.FileMode  { return fs.ModeDir }
+func (s dirInfo) ModTime() time.Time { return s.modtime }
+func (s dirInfo) IsDir() bool        { return true }
+func (s dirInfo) Sys() any           { return nil }
+
+// dirFile is a directory opened from an FS.
+type dirFile struct {
+	fs     *FS
+	info   dirInfo
+	list   dirList
+	closed bool
+}
+
+// Name returns the full normalized name of the directory.
+func (f *dirFile) Name() string {
+	return f.info.name
+}
+
+// Stat implements [fs.File].
+func (f *dirFile) Stat() (fs.FileInfo, error) {
+	return &f.info, nil
+}
+
+// Read implements [fs.File]. Directories cannot be read.
+func (f *dirFile) Read([]byte) (int, error) {
+	if f.closed {
+		return 0, fs.ErrClosed
+	}
+	return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: errIsDir}
+}
+
+// Close implements [fs.File].
+func (f *dirFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+// ReadDir implements [fs.ReadDirFile].
+func (f *dirFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if f.closed {
+		return nil, fs.ErrClosed
+	}
+	return f.list.next(func() ([]fs.DirEntry, error) {
+		return f.fs.readDirNormalized(".", f.info.name, f.info.name)
+	}, n)
+}
+
+// dirList hands out the entries of a directory across successive calls to
+// ReadDir, as described by [fs.ReadDirFile]. The entries are read with read
+// when first needed.
+type dirList struct {
+	entries []fs.DirEntry
+	read    bool
+	off     int
+}
+
+func (l *dirList) next(read func() ([]fs.DirEntry, error), n int) ([]fs.DirEntry, error) {
+	if !l.read {
+		es, err := read()
+		if err != nil {
+			return nil, err
+		}
+		l.entries, l.read = es, true
+	}
+	rest := l.entries[l.off:]
+	if n <= 0 {
+		l.off = len(l.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	l.off += n
+	return rest[:n], nil
+}
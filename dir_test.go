@@ -0,0 +1,81 @@
+package gomemfs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestDirectories(t *testing.T) {
+	// fstest.TestFS expects invalid names to be rejected
+	d, err := New(StrictPaths(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := old.Add(time.Hour)
+	d.Put("a/b/c.txt", []byte("c"), old, nil)
+	d.Put("a/d.txt", []byte("d"), newer, nil)
+	d.Put("e.txt", []byte("e"), old, nil)
+	if err := fstest.TestFS(d, "a/b/c.txt", "a/d.txt", "e.txt"); err != nil {
+		t.Error(err)
+	}
+	es, err := d.ReadDir("a")
+	if err != nil || len(es) != 2 || es[0].Name() != "b" || !es[0].IsDir() || es[1].Name() != "d.txt" {
+		t.Errorf("ReadDir = %v, %v", es, err)
+	}
+	if fi, err := d.Stat("a"); err != nil || !fi.IsDir() || !fi.ModTime().Equal(newer) {
+		t.Errorf("Stat = %v, %v; want a directory modified at %v", fi, err, newer)
+	}
+	d.Expire("a/b/c.txt")
+	if _, err := d.Stat("a/b"); err == nil {
+		t.Error("empty directory still exists")
+	}
+
+	f, err := d.HTTPFileSystem().Open("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if fis, err := f.Readdir(-1); err != nil || len(fis) != 1 || fis[0].Name() != "d.txt" {
+		t.Errorf("Readdir = %v, %v", fis, err)
+	}
+}
+
+func TestIndexFiles(t *testing.T) {
+	d, err := New(IndexFiles("index.html", "index.htm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("docs/index.htm", []byte("docs"), time.Now(), nil)
+	d.Put("docs/a.txt", []byte("a"), time.Now(), nil)
+	d.Put("site/index.html", []byte("site"), time.Now(), nil)
+	for _, name := range []string{"docs", "docs/"} {
+		if b, err := d.ReadFile(name); err != nil || string(b) != "docs" {
+			t.Errorf("ReadFile(%q) = %q, %v", name, b, err)
+		}
+	}
+	if fi, err := d.Stat("docs"); err != nil || !fi.IsDir() {
+		t.Errorf("Stat = %v, %v; want a directory", fi, err)
+	}
+
+	srv := httptest.NewServer(http.FileServerFS(d))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(b) != "site" {
+		t.Errorf("GET /site = %d %q", resp.StatusCode, b)
+	}
+
+	d.Set(IndexFiles())
+	if _, err := d.ReadFile("docs"); err == nil {
+		t.Error("ReadFile(docs) succeeded without IndexFiles")
+	}
+}
@@ -0,0 +1,38 @@
+package gomemfs
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// EarlyExpiry, if greater than zero, enables probabilistic early expiration
+// (the "XFetch" algorithm) to protect against cache stampedes. As a fulfilled
+// key approaches its expire time, an Open of it is increasingly likely to
+// fulfill it again ahead of time, so that a popular key is usually refreshed
+// by a single caller instead of by every caller at once when it expires. The
+// head start grows with the time the key took to fulfill, scaled by
+// EarlyExpiry; 1 is a good default, and larger values refresh earlier. If the
+// early fulfillment fails, the key is served as before.
+type EarlyExpiry float64
+
+func (fso EarlyExpiry) applyTo(fs *FS) error {
+	if fso < 0 || math.IsNaN(float64(fso)) {
+		return fmt.Errorf("invalid EarlyExpiry %v", float64(fso))
+	}
+	fs.earlyExpiry = float64(fso)
+	return nil
+}
+
+// expiresEarly reports whether k should be fulfilled again although it has
+// not yet expired.
+func (d *FS) expiresEarly(k *key) bool {
+	// must be called with fs.mu Locked
+	if d.earlyExpiry <= 0 || k.expire == nil || k.delta <= 0 || d.sealed {
+		return false
+	}
+	// -ln(r) for r in (0, 1] is exponentially distributed with mean 1
+	gap := float64(k.delta) * d.earlyExpiry * -math.Log(1-rand.Float64())
+	return !d.now().Add(time.Duration(gap)).Before(*k.expire)
+}
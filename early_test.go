@@ -0,0 +1,43 @@
+package gomemfs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEarlyExpiry(t *testing.T) {
+	if _, err := New(EarlyExpiry(-1)); err == nil {
+		t.Error("New accepted a negative EarlyExpiry")
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	var fail error
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls++
+		if fail != nil {
+			return nil, nil, nil, fail
+		}
+		// a slow fulfillment whose key is about to expire
+		time.Sleep(5 * time.Millisecond)
+		expire := now.Add(time.Nanosecond)
+		return []byte{byte('0' + calls)}, nil, &expire, nil
+	})
+	d.ReadFile("a")
+	d.ReadFile("a")
+	if calls != 1 {
+		t.Fatalf("fulfilled %d times without EarlyExpiry, want 1", calls)
+	}
+	d.Set(EarlyExpiry(1))
+	if b, err := d.ReadFile("a"); err != nil || string(b) != "2" || calls != 2 {
+		t.Errorf("ReadFile = %q, %v after %d calls, want an early refresh", b, err, calls)
+	}
+	fail = errors.New("backend down")
+	if b, err := d.ReadFile("a"); err != nil || string(b) != "2" || calls != 3 {
+		t.Errorf("ReadFile = %q, %v after %d calls, want the held key", b, err, calls)
+	}
+}
@@ -0,0 +1,99 @@
+package gomemfs
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// EncryptContent returns an FSOption that causes the content of keys to be
+// held encrypted with AES-GCM under key, which must be 16, 24 or 32 bytes
+// long, and only decrypted when it is read, so that heap dumps and core files
+// do not expose it. Every read decrypts a fresh copy of the content, which
+// costs time and memory. Keys already held when the option is set are not
+// encrypted, and exports such as Save and WriteTar write plain content.
+func EncryptContent(key []byte) FSOption {
+	return encryptOption(bytes.Clone(key))
+}
+
+type encryptOption []byte
+
+func (fso encryptOption) applyTo(fs *FS) error {
+	b, err := aes.NewCipher(fso)
+	if err != nil {
+		return fmt.Errorf("invalid encryption key: %w", err)
+	}
+	fs.aead, err = cipher.NewGCM(b)
+	return err
+}
+
+// errTruncated is returned when encrypted content is too short to hold a nonce.
+var errTruncated = errors.New("encrypted content is truncated")
+
+// prepare readies k to be held by d: its content is hashed for VerifyContent,
+// encrypted for EncryptContent and copied for ArenaSize.
+func (d *FS) prepare(k *key) error {
+	// must be called with fs.mu Locked
+	if d.verify && k.aead == nil {
+		k.sum()
+	}
+	b := k.bytes
+	if err := d.encrypt(k); err != nil {
+		return err
+	}
+	if d.arena != nil && k.mapping == nil {
+		k.bytes = d.arena.copy(k.bytes)
+	}
+	if d.wipe && !k.wipe && k.mapping == nil {
+		if len(b) > 0 && len(k.bytes) > 0 && &b[0] == &k.bytes[0] {
+			// the buffer belongs to the caller, or is shared with a Source
+			k.bytes = bytes.Clone(b)
+		}
+		k.wipe = true
+	}
+	return nil
+}
+
+// encrypt replaces the content of k with its encryption, if EncryptContent is
+// set. The buffer that k held is not modified.
+func (d *FS) encrypt(k *key) error {
+	if d.aead == nil || k.aead != nil || k.bytes == nil {
+		return nil
+	}
+	ns := d.aead.NonceSize()
+	nonce := make([]byte, ns, ns+len(k.bytes)+d.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("cannot encrypt content: %w", err)
+	}
+	k.bytes = d.aead.Seal(nonce, nonce, k.bytes, nil)
+	k.aead, k.mapping = d.aead, nil
+	return nil
+}
+
+// plain returns the content of k, decrypting it if needed. The result must
+// not be modified, as it may be the buffer held by k.
+func (k *key) plain() ([]byte, error) {
+	if k.aead == nil {
+		return k.bytes, nil
+	}
+	ns := k.aead.NonceSize()
+	if len(k.bytes) < ns {
+		return nil, &CorruptError{Name: k.name, Err: errTruncated}
+	}
+	b, err := k.aead.Open(nil, k.bytes[:ns], k.bytes[ns:], nil)
+	if err != nil {
+		return nil, &CorruptError{Name: k.name, Err: err}
+	}
+	return b, nil
+}
+
+// size returns the length of the content of k, without decrypting it.
+func (k *key) size() int64 {
+	if k.aead == nil {
+		return int64(len(k.bytes))
+	}
+	return int64(len(k.bytes) - k.aead.NonceSize() - k.aead.Overhead())
+}
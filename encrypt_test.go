@@ -0,0 +1,45 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestEncryptContent(t *testing.T) {
+	if _, err := New(EncryptContent([]byte("short"))); err == nil {
+		t.Error("New accepted a 5 byte key")
+	}
+	d, err := New(EncryptContent(bytes.Repeat([]byte{1}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain := []byte("attack at dawn")
+	d.Put("msg", plain, time.Now(), nil)
+	plain[0] = 'A'
+
+	k := d.keys.get("msg")
+	if k.aead == nil || bytes.Contains(k.bytes, []byte("ttack at dawn")) {
+		t.Errorf("content held in the clear: %q", k.bytes)
+	}
+	if b, err := d.ReadFile("msg"); err != nil || string(b) != "attack at dawn" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	f, err := d.Open("msg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(f)
+	f.Close()
+	if err != nil || string(b) != "attack at dawn" {
+		t.Errorf("Read = %q, %v", b, err)
+	}
+
+	k.bytes[len(k.bytes)-1] ^= 1
+	var ce *CorruptError
+	if _, err := d.ReadFile("msg"); !errors.As(err, &ce) || ce.Err == nil {
+		t.Errorf("ReadFile of tampered content = %v, want a CorruptError", err)
+	}
+}
@@ -0,0 +1,189 @@
+package gomemfs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"time"
+)
+
+// An EventKind identifies what happened to a key in an FS.
+type EventKind int
+
+const (
+	// EventPut is sent when a key is stored with FS.Put.
+	EventPut EventKind = iota + 1
+
+	// EventFulfilled is sent when a Fulfiller produces content for a key,
+	// whether or not the content is cached.
+	EventFulfilled
+
+	// EventExpired is sent when a key is discarded because it has expired.
+	EventExpired
+
+	// EventEvicted is sent when the FS discards a key before it has expired.
+	EventEvicted
+
+	// EventRemoved is sent when a key is removed by a call to FS.Expire.
+	EventRemoved
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventPut:
+		return "put"
+	case EventFulfilled:
+		return "fulfilled"
+	case EventExpired:
+		return "expired"
+	case EventEvicted:
+		return "evicted"
+	case EventRemoved:
+		return "removed"
+	}
+	return "unknown"
+}
+
+// An Event describes a change to a key in an FS. It does not carry the
+// content of the key.
+type Event struct {
+	Kind    EventKind
+	Name    string
+	Size    int64
+	ModTime time.Time
+	Expire  *time.Time
+
+	// Replaced is set on an EventPut or EventFulfilled that replaced a key of
+	// the same name, as opposed to creating it.
+	Replaced bool
+
+	// Time is when the event occurred.
+	Time time.Time
+}
+
+type subscription struct {
+	c chan Event
+
+	// match, if not nil, selects the events sent to c.
+	match func(name string) bool
+}
+
+// Subscribe returns a channel that receives an Event for every change to the
+// FS, buffered to hold n events, and a function that cancels the subscription
+// and closes the channel. Events are sent while the FS is locked, so they are
+// never allowed to block: if the channel is full, the event is dropped. A
+// subscriber that cannot afford to miss events should use a generous buffer.
+func (d *FS) Subscribe(n int) (<-chan Event, func()) {
+	s := &subscription{c: make(chan Event, n)}
+	return s.c, d.subscribe(s)
+}
+
+// subscribe adds s to the subscriptions of d and returns a function that
+// cancels it.
+func (d *FS) subscribe(s *subscription) func() {
+	d.mu.Lock()
+	d.subs = append(d.subs, s)
+	d.unlock()
+
+	cancel := func() {
+		d.mu.Lock()
+		defer d.unlock()
+		for i := range d.subs {
+			if d.subs[i] == s {
+				d.subs = append(d.subs[:i], d.subs[i+1:]...)
+				close(s.c)
+				return
+			}
+		}
+	}
+	return cancel
+}
+
+// Watch is like Subscribe, but only receives events for keys whose names
+// match pattern, in the syntax of [path.Match]. As for IncludeGlob, a pattern
+// without a slash is matched against each element of the name, and a pattern
+// with a slash against the whole name and each of its parent directories. An
+// EventPut or EventFulfilled with Replaced unset means the key was created;
+// EventRemoved, EventExpired and EventEvicted mean it is gone. This allows eg
+// live-reload tooling to watch "*.css".
+func (d *FS) Watch(pattern string, n int) (<-chan Event, func(), error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, nil, fmt.Errorf("cannot watch %q: %w", pattern, err)
+	}
+	patterns := []string{pattern}
+	s := &subscription{
+		c:     make(chan Event, n),
+		match: func(name string) bool { return matchAny(patterns, name) },
+	}
+	return s.c, d.subscribe(s), nil
+}
+
+// callbackContent returns the content of k to pass to OnExpire or OnEvict,
+// copied if it is a mapped file, which may be unmapped once k is gone.
+func (k *key) callbackContent() []byte {
+	b, _ := k.content()
+	if k.mapping != nil {
+		b = bytes.Clone(b)
+	}
+	return b
+}
+
+func (d *FS) emit(ctx context.Context, kind EventKind, k *key) {
+	// must be called with fs.mu Locked
+	d.emitEvent(ctx, kind, k, false)
+}
+
+// emitPut emits an EventPut for k, which replaced a key if replaced is set.
+func (d *FS) emitPut(k *key, replaced bool) {
+	// must be called with fs.mu Locked
+	d.emitEvent(context.Background(), EventPut, k, replaced)
+}
+
+func (d *FS) emitEvent(ctx context.Context, kind EventKind, k *key, replaced bool) {
+	// must be called with fs.mu Locked
+	switch kind {
+	case EventPut:
+		d.logKey(ctx, d.logLevels.Put, "gomemfs put", k)
+	case EventFulfilled:
+		d.logKey(ctx, d.logLevels.Fulfill, "gomemfs fulfilled", k)
+	case EventExpired:
+		d.logKey(ctx, d.logLevels.Expire, "gomemfs expired", k)
+	case EventEvicted:
+		d.logKey(ctx, d.logLevels.Evict, "gomemfs evicted", k)
+	case EventRemoved:
+		d.logKey(ctx, d.logLevels.Remove, "gomemfs removed", k)
+	}
+
+	switch {
+	case kind == EventExpired && d.onExpire != nil:
+		f, name, content := d.onExpire, k.name, k.callbackContent()
+		d.later(func() { f(name, content) })
+	case (kind == EventEvicted || kind == EventRemoved) && d.onEvict != nil:
+		f, name, content := d.onEvict, k.name, k.callbackContent()
+		d.later(func() { f(name, content) })
+	}
+
+	if len(d.subs) == 0 {
+		return
+	}
+	ev := Event{
+		Kind:    kind,
+		Name:    k.name,
+		Size:    k.size(),
+		ModTime: k.modtime,
+		Expire:  k.expire,
+		Time:    d.now(),
+
+		Replaced: replaced,
+	}
+	for _, s := range d.subs {
+		if s.match != nil && !s.match(ev.Name) {
+			continue
+		}
+		select {
+		case s.c <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package gomemfs
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// drain returns the events buffered in c.
+func drain(c <-chan Event) []Event {
+	var evs []Event
+	for {
+		select {
+		case ev, ok := <-c:
+			if !ok {
+				return evs
+			}
+			evs = append(evs, ev)
+		default:
+			return evs
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return []byte(p), nil, nil, nil
+	})
+	c, cancel := d.Subscribe(10)
+	d.Put("a", []byte("a"), time.Now(), nil)
+	d.Put("a", []byte("aa"), time.Now(), nil)
+	d.ReadFile("b")
+	d.Expire("a")
+
+	evs := drain(c)
+	want := []struct {
+		kind     EventKind
+		name     string
+		replaced bool
+	}{
+		{EventPut, "a", false},
+		{EventPut, "a", true},
+		{EventFulfilled, "b", false},
+		{EventRemoved, "a", false},
+	}
+	if len(evs) != len(want) {
+		t.Fatalf("events = %+v", evs)
+	}
+	for i, w := range want {
+		if ev := evs[i]; ev.Kind != w.kind || ev.Name != w.name || ev.Replaced != w.replaced {
+			t.Errorf("event %d = %v %q %v, want %v %q %v", i, ev.Kind, ev.Name, ev.Replaced, w.kind, w.name, w.replaced)
+		}
+	}
+	if evs[1].Size != 2 {
+		t.Errorf("size = %d, want 2", evs[1].Size)
+	}
+
+	// a full channel drops events rather than blocking the FS
+	small, cancelSmall := d.Subscribe(1)
+	defer cancelSmall()
+	d.Put("x", nil, time.Now(), nil)
+	d.Put("y", nil, time.Now(), nil)
+	if evs := drain(small); len(evs) != 1 || evs[0].Name != "x" {
+		t.Errorf("events = %+v", evs)
+	}
+
+	cancel()
+	drain(c)
+	if _, ok := <-c; ok {
+		t.Error("channel not closed by cancel")
+	}
+	cancel()
+}
+
+func TestWatch(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := d.Watch("[", 1); err == nil {
+		t.Error("Watch accepted a malformed pattern")
+	}
+	css, cancel, err := d.Watch("*.css", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancel()
+	dir, cancelDir, err := d.Watch("static/img", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelDir()
+	for _, name := range []string{"app.css", "static/site.css", "app.js", "static/img/logo.png", "img/x.png"} {
+		d.Put(name, []byte("x"), time.Now(), nil)
+	}
+	d.Expire("app.css")
+
+	var names []string
+	for _, ev := range drain(css) {
+		names = append(names, ev.Kind.String()+" "+ev.Name)
+	}
+	if want := []string{"put app.css", "put static/site.css", "removed app.css"}; !slices.Equal(names, want) {
+		t.Errorf("*.css events = %q, want %q", names, want)
+	}
+	if evs := drain(dir); len(evs) != 1 || evs[0].Name != "static/img/logo.png" {
+		t.Errorf("static/img events = %+v", evs)
+	}
+}
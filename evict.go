@@ -0,0 +1,100 @@
+package gomemfs
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"slices"
+	"weak"
+)
+
+// EvictUnderPressure, if greater than zero, causes fulfilled keys to be
+// evicted, least recently used first, whenever the live Go heap exceeds that
+// fraction of the memory limit set with GOMEMLIMIT or [debug.SetMemoryLimit].
+// This lets the cache use memory that is free but yield it back when the
+// process needs it. The heap is checked after every garbage collection. Keys
+// that were put are never evicted, as they cannot be fulfilled again, and
+// nothing is evicted if there is no memory limit. It must be less than 1.
+type EvictUnderPressure float64
+
+func (fso EvictUnderPressure) applyTo(fs *FS) error {
+	if fso < 0 || fso >= 1 || math.IsNaN(float64(fso)) {
+		return fmt.Errorf("invalid EvictUnderPressure %v", float64(fso))
+	}
+	fs.pressure = float64(fso)
+	if fs.pressure > 0 && !fs.sweeping {
+		fs.sweeping = true
+		watchGC(weak.Make(fs))
+	}
+	return nil
+}
+
+// gcSentinel is allocated only to be collected. It holds a pointer so that it
+// is never batched with other small objects, which would delay its cleanup.
+type gcSentinel struct {
+	_ *byte
+}
+
+// watchGC calls sweep on the FS after every garbage collection, for as long
+// as the FS is reachable.
+func watchGC(w weak.Pointer[FS]) {
+	runtime.AddCleanup(&gcSentinel{}, func(w weak.Pointer[FS]) {
+		d := w.Value()
+		if d == nil {
+			return
+		}
+		d.sweep()
+		watchGC(w)
+	}, w)
+}
+
+// sweep evicts keys for EvictUnderPressure, if the heap is over the limit. It
+// runs on the runtime's cleanup goroutine, so OnEvict callbacks for the evicted
+// keys are run on another goroutine rather than holding it up.
+func (d *FS) sweep() {
+	limit := debug.SetMemoryLimit(-1)
+	if limit == math.MaxInt64 {
+		return
+	}
+	s := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(s)
+	if s[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	d.mu.Lock()
+	defer d.unlockAsync()
+	if d.pressure <= 0 {
+		return
+	}
+	d.evict(int64(s[0].Value.Uint64()) - int64(float64(limit)*d.pressure))
+}
+
+// evict evicts fulfilled keys, least recently used first, until their sizes
+// add up to at least excess bytes. Nothing is evicted from a sealed FS.
+func (d *FS) evict(excess int64) {
+	// must be called with fs.mu Locked
+	if excess <= 0 || d.sealed {
+		return
+	}
+	var ks []*key
+	for _, k := range d.keys.all() {
+		if k != nil && k.fulfilled {
+			ks = append(ks, k)
+		}
+	}
+	slices.SortFunc(ks, func(a, b *key) int { return cmp.Compare(a.used, b.used) })
+	for _, k := range ks {
+		if excess <= 0 {
+			break
+		}
+		d.keys.remove(d.fold(k.name))
+		// previous versions are not kept, as memory is needed
+		d.discard(k)
+		d.emit(context.Background(), EventEvicted, k)
+		excess -= k.size()
+	}
+}
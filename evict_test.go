@@ -0,0 +1,105 @@
+package gomemfs
+
+import (
+	"math"
+	"runtime/debug"
+	"testing"
+	"time"
+)
+
+func TestEvict(t *testing.T) {
+	for _, p := range []float64{-0.1, 1, math.NaN()} {
+		if _, err := New(EvictUnderPressure(p)); err == nil {
+			t.Errorf("New accepted EvictUnderPressure(%v)", p)
+		}
+	}
+	d, err := New(EvictUnderPressure(0.9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		expire := time.Now().Add(time.Hour)
+		return []byte("0123456789"), nil, &expire, nil
+	})
+	d.Put("put", []byte("0123456789"), time.Now(), nil)
+	for _, name := range []string{"a", "b", "c", "a"} {
+		if _, err := d.ReadFile(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d.mu.Lock()
+	d.evict(15)
+	d.unlock()
+	if d.Exists("b") || d.Exists("c") {
+		t.Error("least recently used keys not evicted")
+	}
+	if !d.Exists("a") || !d.Exists("put") {
+		t.Error("evicted more than needed")
+	}
+
+	d.mu.Lock()
+	d.evict(100)
+	d.unlock()
+	if d.Exists("a") || !d.Exists("put") {
+		t.Error("want every fulfilled key evicted, and no put key")
+	}
+}
+
+func TestEvictSealed(t *testing.T) {
+	d, err := New(EvictUnderPressure(0.9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		expire := time.Now().Add(time.Hour)
+		return []byte("0123456789"), nil, &expire, nil
+	})
+	if _, err := d.ReadFile("a"); err != nil {
+		t.Fatal(err)
+	}
+	d.Seal()
+	d.mu.Lock()
+	d.evict(100)
+	d.unlock()
+	if !d.Exists("a") {
+		t.Error("key evicted from a sealed FS")
+	}
+}
+
+func TestSweepDoesNotWaitForOnEvict(t *testing.T) {
+	release := make(chan struct{})
+	evicted := make(chan string, 1)
+	d, err := New(OnEvict(func(name string, content []byte) {
+		<-release
+		evicted <- name
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		expire := time.Now().Add(time.Hour)
+		return []byte("0123456789"), nil, &expire, nil
+	})
+	if _, err := d.ReadFile("a"); err != nil {
+		t.Fatal(err)
+	}
+	// set directly, so that only this sweep runs
+	d.pressure = 0.5
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(1))
+
+	done := make(chan struct{})
+	go func() {
+		d.sweep()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("sweep waited for OnEvict")
+	}
+	close(release)
+	if name := <-evicted; name != "a" {
+		t.Errorf("OnEvict(%q), want a", name)
+	}
+}
@@ -0,0 +1,63 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+// Fallback returns an FSOption that causes an FS to consult fsys for a key
+// only after every Fulfiller has returned nil content. Unlike a Fulfiller
+// created with Compose, results from fsys keep the modtime of the original
+// file and are not cached unless FallbackTTL is set. Passing a nil fsys
+// removes the fallback.
+func Fallback(fsys fs.FS) FSOption {
+	return fallbackOption{fsys: fsys}
+}
+
+type fallbackOption struct {
+	fsys fs.FS
+}
+
+func (fso fallbackOption) applyTo(fs *FS) error {
+	fs.fallback = fso.fsys
+	return nil
+}
+
+// FallbackTTL, if greater than zero, causes content obtained from a Fallback
+// to be cached for that long.
+type FallbackTTL time.Duration
+
+func (fso FallbackTTL) applyTo(fs *FS) error {
+	fs.fallbackTTL = time.Duration(fso)
+	return nil
+}
+
+// fulfillFallback reads name from fsys, the Fallback of an FS, returning nil
+// content if fsys does not have it.
+func fulfillFallback(fsys fs.FS, name string) ([]byte, *time.Time, error) {
+	f, err := fsys.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot open %q in fallback %T: %w", name, fsys, err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot stat %q in fallback %T: %w", name, fsys, err)
+	}
+	if fi.IsDir() {
+		return nil, nil, nil
+	}
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read %q in fallback %T: %w", name, fsys, err)
+	}
+	mt := fi.ModTime()
+	return buf, &mt, nil
+}
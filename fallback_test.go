@@ -0,0 +1,57 @@
+package gomemfs
+
+import (
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestFallback(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	d, err := New(Fallback(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("a.txt"); err != nil || string(b) != "a" {
+		t.Fatalf("ReadFile = %q, %v", b, err)
+	}
+	if d.Exists("a.txt") {
+		t.Error("fallback content cached without FallbackTTL")
+	}
+	if err := d.Set(FallbackTTL(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	d.ReadFile("a.txt")
+	if !d.Exists("a.txt") {
+		t.Error("fallback content not cached with FallbackTTL")
+	}
+}
+
+func TestFallbackSetWhileFulfilling(t *testing.T) {
+	fsys := fstest.MapFS{"a.txt": {Data: []byte("a")}}
+	d, err := New(Fallback(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 200 {
+			d.ReadFile("a.txt")
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			if i%2 == 0 {
+				d.Set(Fallback(nil))
+			} else {
+				d.Set(Fallback(fsys))
+			}
+			d.Set(Clock(time.Now))
+		}
+	}()
+	wg.Wait()
+}
@@ -6,83 +6,135 @@ import (
 	"io/fs"
 )
 
+// A File is an open key of an FS. Its content is fixed when it is opened, so
+// it is unaffected by the key later being replaced or expiring.
+//
+// Closing a File is cheap and may be done more than once. After Close, methods
+// that read content return [fs.ErrClosed], while Name and Stat keep working,
+// so a closed File can safely be held on to for its metadata. Reopen returns
+// a new File over the same content.
 type File struct {
-	r *bytes.Reader
-	k *key
+	r      bytes.Reader
+	closed bool
+	k      *key
+
+	// data is the content read by r, decrypted if needed.
+	data []byte
+
+	// dir is set when the File was opened by resolving a directory name
+	// with IndexFiles.
+	dir  string
+	list *dirList
 }
 
-// Close releases the bytes.Reader for this object. It
-// implements [fs.File].
+// Close releases the content of this object. It implements [fs.File].
 func (f *File) Close() error {
-	f.r = nil
+	f.closed = true
+	f.r.Reset(nil)
 	return nil
 }
 
+// Reopen returns a new File over the same content as f, positioned at its
+// start, whether or not f has been closed. The FS is not consulted, so this
+// works even if the key has since been replaced or has expired.
+func (f *File) Reopen() *File {
+	nf := &File{data: f.data, k: f.k, dir: f.dir}
+	nf.r.Reset(nf.data)
+	return nf
+}
+
+// Name returns the full normalized name of the key the File was opened from,
+// such as "css/app.css". If the File was opened by resolving a directory name
+// with IndexFiles, it is the name of the index file.
+func (f *File) Name() string {
+	return f.k.name
+}
+
 // Stat implements [fs.File].
 func (f *File) Stat() (fs.FileInfo, error) {
-	return &FileStat{k: f.k}, nil
+	if f.dir == "" {
+		return f.k.fileStat(), nil
+	}
+	return &FileStat{k: f.k, dir: f.dir}, nil
 }
 
 // Read implements [fs.File].
-func (f File) Read(b []byte) (int, error) {
-	if f.r == nil {
+func (f *File) Read(b []byte) (int, error) {
+	if f.closed {
 		return 0, fs.ErrClosed
 	}
 	return f.r.Read(b)
 }
 
 // ReadAt implements [io.ReaderAt].
-func (f File) ReadAt(b []byte, off int64) (int, error) {
-	if f.r == nil {
+func (f *File) ReadAt(b []byte, off int64) (int, error) {
+	if f.closed {
 		return 0, fs.ErrClosed
 	}
 	return f.r.ReadAt(b, off)
 }
 
 // ReadByte implements [io.ByteScanner].
-func (f File) ReadByte() (byte, error) {
-	if f.r == nil {
+func (f *File) ReadByte() (byte, error) {
+	if f.closed {
 		return 0, fs.ErrClosed
 	}
 	return f.r.ReadByte()
 }
 
 // UnreadByte implements [io.ByteScanner].
-func (f File) UnreadByte() error {
-	if f.r == nil {
+func (f *File) UnreadByte() error {
+	if f.closed {
 		return fs.ErrClosed
 	}
 	return f.r.UnreadByte()
 }
 
 // ReadRune implements [io.RuneScanner].
-func (f File) ReadRune() (rune, int, error) {
-	if f.r == nil {
+func (f *File) ReadRune() (rune, int, error) {
+	if f.closed {
 		return 0, 0, fs.ErrClosed
 	}
 	return f.r.ReadRune()
 }
 
 // UnreadRune implements [io.RuneScanner].
-func (f File) UnreadRune() error {
-	if f.r == nil {
+func (f *File) UnreadRune() error {
+	if f.closed {
 		return fs.ErrClosed
 	}
 	return f.r.UnreadRune()
 }
 
 // Seek implements [io.Seeker].
-func (f File) Seek(offset int64, whence int) (int64, error) {
-	if f.r == nil {
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
 		return 0, fs.ErrClosed
 	}
 	return f.r.Seek(offset, whence)
 }
 
 // WriteTo implements [io.WriterTo].
-func (f File) WriteTo(w io.Writer) (n int64, err error) {
-	if f.r == nil {
+func (f *File) WriteTo(w io.Writer) (n int64, err error) {
+	if f.closed {
 		return 0, fs.ErrClosed
 	}
 	return f.r.WriteTo(w)
 }
+
+// ReadDir implements [fs.ReadDirFile] for a File that was opened by resolving
+// a directory name with IndexFiles. For any other File it returns an error.
+func (f *File) ReadDir(n int) ([]fs.DirEntry, error) {
+	if f.closed {
+		return nil, fs.ErrClosed
+	}
+	if f.dir == "" {
+		return nil, &fs.PathError{Op: "readdir", Path: f.k.name, Err: errNotDir}
+	}
+	if f.list == nil {
+		f.list = &dirList{}
+	}
+	return f.list.next(func() ([]fs.DirEntry, error) {
+		return f.k.fs.readDirNormalized(".", f.dir, f.dir)
+	}, n)
+}
@@ -0,0 +1,86 @@
+package gomemfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestFileName(t *testing.T) {
+	d, err := New(IndexFiles("index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("css/app.css", []byte("x"), time.Now(), nil)
+	d.Put("docs/index.html", []byte("x"), time.Now(), nil)
+	for name, want := range map[string]string{
+		"css/app.css":  "css/app.css",
+		"/css/app.css": "css/app.css",
+		"docs":         "docs/index.html",
+	} {
+		f, err := d.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := f.(*File).Name(); got != want {
+			t.Errorf("Open(%q).Name() = %q, want %q", name, got, want)
+		}
+		f.Close()
+	}
+}
+
+func TestFileClose(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a", []byte("abc"), time.Now(), nil)
+	f, err := d.Open("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mf := f.(*File)
+	buf := make([]byte, 1)
+	mf.Read(buf)
+	if err := mf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := mf.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+	if _, err := mf.Read(buf); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("Read after Close = %v, want fs.ErrClosed", err)
+	}
+	if _, err := mf.Seek(0, io.SeekStart); !errors.Is(err, fs.ErrClosed) {
+		t.Errorf("Seek after Close = %v, want fs.ErrClosed", err)
+	}
+	if fi, err := mf.Stat(); err != nil || fi.Size() != 3 || mf.Name() != "a" {
+		t.Errorf("Stat after Close = %v, %v", fi, err)
+	}
+
+	d.Put("a", []byte("new"), time.Now(), nil)
+	r := mf.Reopen()
+	if b, err := io.ReadAll(r); err != nil || string(b) != "abc" {
+		t.Errorf("Reopen read %q, %v; want the original content", b, err)
+	}
+}
+
+func TestHotPathAllocs(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a/b.txt", []byte("x"), time.Now(), nil)
+	if n := testing.AllocsPerRun(100, func() { d.Stat("a/b.txt") }); n != 0 {
+		t.Errorf("Stat of a held key made %v allocations, want 0", n)
+	}
+	if n := testing.AllocsPerRun(100, func() {
+		f, _ := d.Open("a/b.txt")
+		f.Stat()
+		f.Close()
+	}); n > 1 {
+		t.Errorf("Open, Stat and Close of a held key made %v allocations, want 1", n)
+	}
+}
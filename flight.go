@@ -0,0 +1,95 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+)
+
+// errAborted is returned to callers waiting on a fulfillment that panicked.
+var errAborted = errors.New("fulfillment did not complete")
+
+// A flight is a fulfillment in progress. The FS is not locked while Sources
+// run, so that a slow fulfillment does not hold up other keys; callers that
+// need the same key meanwhile wait for its flight instead of fulfilling it
+// again.
+type flight struct {
+	done chan struct{}
+	k    *key
+	err  error
+}
+
+// takeoff records that name is being fulfilled for ctx.
+func (d *FS) takeoff(ctx context.Context, name string) *flight {
+	// must be called with fs.mu Locked
+	if !shared(ctx) {
+		return &flight{done: make(chan struct{})}
+	}
+	if d.flights == nil {
+		d.flights = make(map[string]*flight)
+	}
+	f := &flight{done: make(chan struct{})}
+	d.flights[d.fold(name)] = f
+	return f
+}
+
+// takeoffStat records that name is being described by StatSources. These
+// flights are kept apart from those of fulfillments, since their result may
+// be a key without content.
+func (d *FS) takeoffStat(ctx context.Context, name string) *flight {
+	// must be called with fs.mu Locked
+	if !shared(ctx) {
+		return &flight{done: make(chan struct{})}
+	}
+	if d.stats == nil {
+		d.stats = make(map[string]*flight)
+	}
+	f := &flight{done: make(chan struct{})}
+	d.stats[d.fold(name)] = f
+	return f
+}
+
+// shared reports whether a fulfillment for ctx may be shared with other
+// callers, by waiting for its flight. A ContextSource may return different
+// content for the same name depending on the values attached with
+// WithRequestValues, so such fulfillments run on their own and are not waited
+// for.
+func shared(ctx context.Context) bool {
+	return RequestValues(ctx) == nil
+}
+
+// land completes the flight f for name with its result, releasing any callers
+// waiting for it.
+func (d *FS) land(name string, f *flight, k *key, err error) {
+	// must be called with fs.mu Locked
+	if d.flights[d.fold(name)] == f {
+		delete(d.flights, d.fold(name))
+	} else if d.stats[d.fold(name)] == f {
+		delete(d.stats, d.fold(name))
+	}
+	if k == nil && err == nil {
+		err = errAborted
+	}
+	f.k, f.err = k, err
+	close(f.done)
+}
+
+// await waits for the flight f to land, or for ctx to be done.
+func (d *FS) await(ctx context.Context, f *flight) (*key, error) {
+	// must be called with fs.mu Locked, which is released while waiting
+	d.mu.Unlock()
+	defer d.mu.Lock()
+	select {
+	case <-f.done:
+		return f.k, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// unlocked calls f with d unlocked.
+func (d *FS) unlocked(f func()) {
+	// must be called with fs.mu Locked
+	d.mu.Unlock()
+	defer d.mu.Lock()
+	f()
+}
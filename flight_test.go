@@ -0,0 +1,48 @@
+package gomemfs
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestConcurrentFulfillment(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	var calls atomic.Int32
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls.Add(1)
+		if p == "slow" {
+			<-release
+		}
+		expire := time.Now().Add(time.Hour)
+		return []byte(p), nil, &expire, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b, err := d.ReadFile("slow"); err != nil || string(b) != "slow" {
+				t.Errorf("ReadFile = %q, %v", b, err)
+			}
+		}()
+	}
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// other keys are fulfilled while slow is in flight
+	if b, err := d.ReadFile("fast"); err != nil || string(b) != "fast" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Fulfiller called %d times, want once for each key", n)
+	}
+}
@@ -2,8 +2,11 @@ package gomemfs
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"path"
 	"strings"
 	"sync"
@@ -16,16 +19,27 @@ import (
 // or more callback functions to fulfill generation.
 type FS struct {
 	mu        sync.Mutex
-	keys      map[string]*key
-	callbacks []Fulfiller
+	keys      keyIndex
+	callbacks []Source
+	subs      []*subscription
+	deferred  []func()
+	sealed    bool
+	breakers  map[int]*breakerState
+	history   map[string][]*key
+	flights   map[string]*flight
+	stats     map[string]*flight
+	wipes     [][]byte
+	sweeping  bool
 
-	caseInsensitive bool
-	statFulfills    bool
+	// tick orders uses of keys, for EvictUnderPressure.
+	tick uint64
+
+	options
 }
 
 func New(o ...FSOption) (*FS, error) {
 	fs := &FS{
-		keys: make(map[string]*key),
+		options: defaultOptions,
 	}
 	for i := range o {
 		if err := o[i].applyTo(fs); err != nil {
@@ -38,131 +52,586 @@ func New(o ...FSOption) (*FS, error) {
 // Set applies an FSOption to an existing FS, if possible.
 func (d *FS) Set(o FSOption) error {
 	d.mu.Lock()
-	defer d.mu.Unlock()
+	defer d.unlock()
+	if err := d.checkSealed("set option"); err != nil {
+		return err
+	}
 	return o.applyTo(d)
 }
 
 // Len reports the number of keys currently stored in FS.
 func (d *FS) Len() int {
 	d.mu.Lock()
-	defer d.mu.Unlock()
-	return len(d.keys)
+	defer d.unlock()
+	return d.keys.len()
 }
 
 // FulfillWith adds one or more Fulfiller callbacks to this FS. Fulfillers are
 // run in LIFO order.
 func (d *FS) FulfillWith(f ...Fulfiller) error {
+	s := make([]Source, len(f))
+	for i := range f {
+		s[i] = f[i]
+	}
+	return d.FulfillFrom(s...)
+}
+
+// FulfillWithTTL adds the Fulfiller f to this FS like FulfillWith, except that
+// content f returns with a nil expire or Forever expires ttl after it is
+// fulfilled, overriding DefaultTTL. This lets Fulfillers have different cache lifetimes
+// without each computing its own expiry.
+func (d *FS) FulfillWithTTL(f Fulfiller, ttl time.Duration) error {
+	if ttl <= 0 {
+		return fmt.Errorf("invalid ttl %v", ttl)
+	}
+	return d.FulfillFrom(&ttlSource{f: f, ttl: ttl})
+}
+
+// ttlSource is a Fulfiller added with FulfillWithTTL. Its ttl is applied by
+// FS.keep, with the FS locked, since the Clock of the FS may be changed while
+// Sources run.
+type ttlSource struct {
+	f   Fulfiller
+	ttl time.Duration
+}
+
+// Fulfill implements Source.
+func (t *ttlSource) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return t.f(name)
+}
+
+// FulfillFrom adds one or more Sources to this FS. Sources and Fulfillers
+// share one list and are run in LIFO order.
+func (d *FS) FulfillFrom(s ...Source) error {
 	d.mu.Lock()
-	defer d.mu.Unlock()
-	d.callbacks = append(d.callbacks, f...)
+	defer d.unlock()
+	if err := d.checkSealed("add fulfillers"); err != nil {
+		return err
+	}
+	d.callbacks = append(d.callbacks, s...)
 	return nil
 }
 
 // Put sets the contents of key name in the FS. If the key already exists, it is
 // replaced. The []byte buffer must not be modified after calling Put; if needed
-// you may use [bytes.Clone] to create a private copy for Put.
-func (d *FS) Put(name string, content []byte, modtime time.Time, expire *time.Time) error {
-	n, err := d.normalize(name)
-	if err != nil {
-		return fmt.Errorf("cannot put key %q: %w", name, err)
-	}
-	d.mu.Lock()
+// you may use [bytes.Clone] to create a private copy for Put. Options such as
+// Mode set further metadata on the key. A nil expire means the key never
+// expires, unless DefaultTTL is set.
+func (d *FS) Put(name string, content []byte, modtime time.Time, expire *time.Time, o ...PutOption) error {
 	k := &key{
 		bytes:   content,
-		name:    n,
+		name:    name,
 		modtime: modtime,
-		expire:  expire,
-		fs:      d,
+		expire:  d.defaultExpire(expire),
+	}
+	for _, opt := range o {
+		opt.applyToKey(k)
+	}
+	return d.putKey(k)
+}
+
+func (d *FS) putKey(k *key) error {
+	n, err := d.normalize(k.name)
+	if err != nil {
+		return &fs.PathError{Op: "put", Path: k.name, Err: err}
+	}
+	name := k.name
+	k.name = n
+	return d.putNamed(name, k)
+}
+
+// putNamed stores k, whose name is already normalized, reporting errors for
+// name, as given by the caller.
+func (d *FS) putNamed(name string, k *key) error {
+	k.fs = d
+	d.mu.Lock()
+	defer d.unlock()
+	if err := d.checkSealed("put key"); err != nil {
+		return &fs.PathError{Op: "put", Path: name, Err: err}
+	}
+	if err := d.store(k); err != nil {
+		return &fs.PathError{Op: "put", Path: name, Err: err}
 	}
-	d.keys[n] = k
-	d.mu.Unlock()
 	return nil
 }
 
+func (d *FS) store(k *key) error {
+	// must be called with fs.mu Locked, with k.name normalized
+	n := k.name
+	if err := d.admit(k); err != nil {
+		return err
+	}
+	old := d.lookup(n)
+	if old != nil && d.casePreserving {
+		k.name = old.name
+	}
+	k.stored = d.now()
+	d.keys.set(d.fold(n), k)
+	d.retire(old)
+	d.emitPut(k, old != nil)
+	return nil
+}
+
+// admit checks that k can be stored alongside the keys the FS holds, under
+// the limits set with MaxEntryBytes, MaxKeys and Quota, and prepares its
+// content to be stored.
+func (d *FS) admit(k *key) error {
+	// must be called with fs.mu Locked, with k.name normalized
+	if err := d.checkSize(k.name, k.bytes); err != nil {
+		return err
+	}
+	if err := d.checkFull(k.name); err != nil {
+		return err
+	}
+	if err := d.checkQuota(k.name, k.size()); err != nil {
+		return err
+	}
+	return d.prepare(k)
+}
+
 func (d *FS) lookup(name string) *key {
 	// must be called with fs.mu Locked
-	k, ok := d.keys[name]
+	name = d.fold(name)
+	k, ok := d.keys.lookup(name)
 	if !ok {
 		return nil
 	}
 	if k == nil {
 		// we're somehow storing a nil pointer
-		delete(d.keys, name)
+		d.keys.remove(name)
 		return nil
 	}
-	if k.expire != nil && time.Now().After(*k.expire) {
+	if k.expire != nil && d.now().After(*k.expire) {
 		// we found a key but it's expired
-		delete(d.keys, name)
+		if !d.sealed {
+			d.keys.remove(name)
+			d.retire(k)
+			d.emit(context.Background(), EventExpired, k)
+		}
 		return nil
 	}
 	return k
 }
 
-func (d *FS) fulfill(name string) (*key, error) {
+func (d *FS) fulfill(ctx context.Context, name string) (*key, error) {
 	// must be called with fs.mu Locked
+	return d.fulfillBelow(ctx, name, len(d.callbacks))
+}
+
+func (d *FS) fulfillBelow(ctx context.Context, name string, top int) (k *key, err error) {
+	// must be called with fs.mu Locked, which is released while Sources run
+	// only the callbacks below index top are consulted
+	ctx, err = d.enter(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if f := d.flights[d.fold(name)]; f != nil && shared(ctx) {
+		return d.await(ctx, f)
+	}
+	f := d.takeoff(ctx, name)
+	defer func() { d.land(name, f, k, err) }()
+	// held is only set when a key is refreshed early; see EarlyExpiry
+	held := d.keys.get(d.fold(name))
+
+	if k, err := d.limit(ctx, name); k != nil || err != nil {
+		return k, err
+	}
 	var content []byte
 	var modtime *time.Time
 	var expire *time.Time
-	var err error
+
+	var span Span
+	start := time.Now()
+	used := -1
+	if d.tracer != nil {
+		// Sources, and their own spans, run beneath this one
+		ctx, span = d.tracer.Start(ctx, TraceFulfill, name)
+		defer func() {
+			r := TraceResult{Fulfiller: used, Bytes: len(content), Duration: time.Since(start), Err: err}
+			if used >= 0 {
+				r.FulfillerName = fulfillerName(d.callbacks[used])
+			}
+			span.End(r)
+		}()
+	}
 
 	// we scan in reverse order! the last added callback is called
 	// first, until we encounter an error or get non-nil content
-	for i := range d.callbacks {
-		idx := len(d.callbacks) - (i + 1)
-		content, modtime, expire, err = d.callbacks[idx](name)
+	miss := &MissError{Name: name}
+	for i := range top {
+		idx := top - (i + 1)
+		if d.skip(idx) {
+			miss.Attempts = append(miss.Attempts, MissAttempt{idx, fulfillerName(d.callbacks[idx]), ErrCircuitOpen})
+			continue
+		}
+		// options may be changed with Set while the FS is unlocked
+		src, retry := d.callbacks[idx], d.retry
+		d.unlocked(func() { content, modtime, expire, err = retry.fulfill(ctx, src, name) })
+		if errors.Is(err, fs.ErrNotExist) {
+			d.record(idx, nil)
+			// a soft error; the next callback may still have name
+			miss.Attempts = append(miss.Attempts, MissAttempt{idx, fulfillerName(d.callbacks[idx]), err})
+			content, err = nil, nil
+			continue
+		}
+		d.record(idx, err)
 		if err != nil {
+			used = idx
+			d.log(ctx, d.logLevels.FulfillError, "gomemfs fulfiller failed",
+				slog.String("name", name),
+				slog.Int("fulfiller", idx),
+				slog.Any("error", err))
 			return nil, err
 		}
 		if content != nil {
+			used = idx
 			break
 		}
+		miss.Attempts = append(miss.Attempts, MissAttempt{idx, fulfillerName(d.callbacks[idx]), nil})
+	}
+	if fsys := d.fallback; content == nil && fsys != nil {
+		d.unlocked(func() { content, modtime, err = fulfillFallback(fsys, name) })
+		if err != nil {
+			return nil, err
+		}
+		// results from a Fallback are not cached unless FallbackTTL is set,
+		// whatever the DefaultTTL
+		expire = &time.Time{}
+		if content != nil && d.fallbackTTL > 0 {
+			e := d.now().Add(d.fallbackTTL)
+			expire = &e
+		}
+		if content == nil {
+			miss.Attempts = append(miss.Attempts, MissAttempt{-1, fmt.Sprintf("fallback %T", fsys), nil})
+		}
 	}
 	if content == nil {
-		return nil, fs.ErrNotExist
+		err = miss
+		return nil, err
+	}
+	var src Source
+	if used >= 0 {
+		src = d.callbacks[used]
+	}
+	k, err = d.keep(ctx, name, held, fulfilled{content, modtime, expire, src, used, time.Since(start)})
+	if err != nil {
+		content = nil
 	}
+	return k, err
+}
+
+// fulfilled is content produced by a Source for a key.
+type fulfilled struct {
+	content []byte
+	modtime *time.Time
+	expire  *time.Time
+
+	// src produced the content, and idx is its index in the callbacks of
+	// the FS, or -1 if it is not one of them.
+	src Source
+	idx int
+
+	// delta is how long the Source took; see EarlyExpiry.
+	delta time.Duration
+}
+
+// keep makes a key of content fulfilled for name and stores it, unless it is
+// not to be cached or the key held when fulfillment started, held, has since
+// been replaced.
+func (d *FS) keep(ctx context.Context, name string, held *key, f fulfilled) (*key, error) {
+	// must be called with fs.mu Locked
+	if t, ok := f.src.(*ttlSource); ok && (f.expire == nil || f.expire.Equal(Forever)) {
+		e := d.now().Add(t.ttl)
+		f.expire = &e
+	}
+	if err := d.checkSize(name, f.content); err != nil {
+		d.log(ctx, d.logLevels.FulfillError, "gomemfs fulfilled content discarded",
+			slog.String("name", name),
+			slog.Int("fulfiller", f.idx),
+			slog.Any("error", err))
+		return nil, err
+	}
+	// if the Fulfiller returns a zero expire time, or a nil one without
+	// DefaultTTL, do not cache; nor if the key was put or removed while the FS
+	// was unlocked, as that is newer
+	expire, cache := f.expire, f.expire != nil && !f.expire.IsZero()
+	if f.expire == nil || f.expire.Equal(Forever) {
+		expire = d.defaultExpire(nil)
+		cache = cache || expire != nil
+	}
+	cache = cache && !d.sealed && d.keys.get(d.fold(name)) == held
+	if cache {
+		if err := d.checkFull(name); err != nil {
+			return nil, err
+		}
+		if err := d.checkQuota(name, int64(len(f.content))); err != nil {
+			return nil, err
+		}
+	}
+	modtime := f.modtime
 	if modtime == nil {
-		var n time.Time = time.Now()
+		var n time.Time = d.now()
+		if d.defaultModTime != nil {
+			n = d.defaultModTime(name)
+		}
 		modtime = &n
 	}
 	k := &key{
-		bytes:   content,
+		bytes:   f.content,
 		name:    name,
 		modtime: *modtime,
 		expire:  expire,
+		delta:   f.delta,
 		fs:      d,
 	}
-	// if the Fulfiller returns a zero expire time, do not cache
-	if expire != nil && !expire.IsZero() {
-		d.keys[name] = k
+	if ms, ok := f.src.(ModeSource); ok {
+		Mode(ms.Mode(name)).applyToKey(k)
 	}
+	if cache {
+		if err := d.prepare(k); err != nil {
+			return nil, err
+		}
+		k.stored = d.now()
+		d.tick++
+		k.used, k.fulfilled = d.tick, true
+		d.keys.set(d.fold(name), k)
+		d.retire(held)
+	}
+	d.emitEvent(ctx, EventFulfilled, k, cache && held != nil)
 	return k, nil
 }
 
+// fetch describes what FS.get may do when a key is not already held.
+type fetch int
+
+const (
+	fetchCached  fetch = iota // report the key as missing
+	fetchFulfill              // fulfill the key
+	fetchStat                 // describe the key using StatSources if possible
+)
+
+func (d *FS) get(ctx context.Context, root, name string, how fetch) (k *key, dir string, err error) {
+	// must be called with fs.mu Locked
+	// if name is a directory, dir is set to name and k is either nil or the
+	// key that was found using IndexFiles; links are resolved within root,
+	// which is "." unless name was given to a SubFS
+	if name, err = d.followIn(root, name); err != nil {
+		return nil, "", err
+	}
+	if err = d.authorized(ctx, name); err != nil {
+		return nil, "", err
+	}
+	k, err = d.fetchKey(ctx, name, how)
+	if errors.Is(err, fs.ErrNotExist) {
+		for _, idx := range d.indexFiles {
+			n, nerr := d.clean(path.Join(name, idx))
+			if nerr != nil {
+				continue
+			}
+			if k, err := d.getKey(ctx, root, n, how); !errors.Is(err, fs.ErrNotExist) {
+				return k, name, err
+			}
+		}
+		if d.isDir(name) {
+			return nil, name, nil
+		}
+		if nf := d.notFoundKey(root, name); nf != "" {
+			k, err = d.getKey(ctx, root, nf, how)
+		}
+	}
+	return k, "", err
+}
+
+func (d *FS) getKey(ctx context.Context, root, name string, how fetch) (*key, error) {
+	// must be called with fs.mu Locked
+	name, err := d.followIn(root, name)
+	if err != nil {
+		return nil, err
+	}
+	return d.fetchKey(ctx, name, how)
+}
+
+func (d *FS) fetchKey(ctx context.Context, name string, how fetch) (*key, error) {
+	// must be called with fs.mu Locked, with name already followed
+	if k := d.lookup(name); k != nil {
+		d.tick++
+		k.used = d.tick
+		if how == fetchFulfill && d.flights[d.fold(name)] == nil && d.expiresEarly(k) {
+			if nk, err := d.fulfill(ctx, name); err == nil {
+				return nk, nil
+			}
+		}
+		k.hits++
+		d.access(k)
+		return k, nil
+	}
+	switch how {
+	case fetchFulfill:
+		return d.fulfill(ctx, name)
+	case fetchStat:
+		return d.describe(ctx, name)
+	}
+	d.traceMiss(ctx, name)
+	return nil, fs.ErrNotExist
+}
+
+func (d *FS) describe(ctx context.Context, name string) (k *key, err error) {
+	// must be called with fs.mu Locked, which is released while Sources run
+	// StatSources are asked to describe name, and the first other Source
+	// that is reached is used to fulfill it instead
+	if f := d.flights[d.fold(name)]; f != nil && shared(ctx) {
+		return d.await(ctx, f)
+	}
+	if f := d.stats[d.fold(name)]; f != nil && shared(ctx) {
+		return d.await(ctx, f)
+	}
+	f := d.takeoffStat(ctx, name)
+	defer func() { d.land(name, f, k, err) }()
+
+	// Sources are only ever appended, so indexes stay valid while unlocked
+	callbacks := d.callbacks
+	for i := range callbacks {
+		idx := len(callbacks) - (i + 1)
+		ss, ok := callbacks[idx].(StatSource)
+		if !ok {
+			return d.fulfillBelow(ctx, name, idx+1)
+		}
+		var fi fs.FileInfo
+		d.unlocked(func() { fi, err = ss.Stat(name) })
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fi != nil && !fi.IsDir() {
+			// a transient key that is never stored
+			return &key{name: name, modtime: fi.ModTime(), fs: d, info: fi}, nil
+		}
+	}
+	return d.fulfillBelow(ctx, name, 0)
+}
+
+// normalize returns the name of the key that name, as passed to the FS, refers
+// to. It must only be called once for each name, as Normalizers such as
+// PercentDecode give a different result when run again.
 func (d *FS) normalize(name string) (string, error) {
-	if d.caseInsensitive {
+	name, err := d.rewrite(name)
+	if err != nil {
+		return "", err
+	}
+	return d.clean(name)
+}
+
+// rewrite runs the Normalizers of the FS on name.
+func (d *FS) rewrite(name string) (string, error) {
+	for _, n := range d.normalizers {
+		var err error
+		if name, err = n(name); err != nil {
+			return "", err
+		}
+	}
+	return name, nil
+}
+
+// clean is normalize without the Normalizers, for names that were already
+// rewritten, such as those of keys held by this or another FS.
+func (d *FS) clean(name string) (string, error) {
+	if d.backslashes {
+		if len(name) >= 2 && name[1] == ':' && ('a' <= name[0]|0x20 && name[0]|0x20 <= 'z') {
+			// a drive letter
+			return "", fs.ErrInvalid
+		}
+		name = strings.ReplaceAll(name, "\\", "/")
+	}
+	if d.strictPaths && (!fs.ValidPath(name) || strings.ContainsRune(name, '\\')) {
+		return "", fs.ErrInvalid
+	}
+	if d.caseInsensitive && !d.casePreserving {
 		name = strings.ToLower(name)
 	}
-	return strings.TrimPrefix(path.Clean(name), "/"), nil
+	if fs.ValidPath(name) {
+		// already clean, as is usual
+		return name, nil
+	}
+	name = strings.TrimPrefix(path.Clean(name), "/")
+	if name == "" {
+		name = "."
+	}
+	return name, nil
+}
+
+// fold returns the name under which a normalized name is held in d.keys.
+func (d *FS) fold(name string) string {
+	if d.casePreserving {
+		return strings.ToLower(name)
+	}
+	return name
 }
 
 // Open implements [fs.FS].
 func (d *FS) Open(name string) (fs.File, error) {
+	return d.OpenContext(context.Background(), name)
+}
+
+// OpenContext is like Open, but ctx is passed along to the Tracer (if any) so
+// that fulfillment spans are attached to the caller's trace. A Fulfiller may
+// read other keys of the same FS; a ContextSource should pass along the
+// context it was given, so that a key that is needed to fulfill itself fails
+// instead of waiting for itself forever.
+func (d *FS) OpenContext(ctx context.Context, name string) (fs.File, error) {
+	return d.openIn(ctx, ".", name)
+}
+
+// openIn is OpenContext, resolving links within root.
+func (d *FS) openIn(ctx context.Context, root, name string) (fs.File, error) {
 	n, err := d.normalize(name)
 	if err != nil {
-		return nil, fmt.Errorf("cannot open key %q: %w", name, err)
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
 	}
 	d.mu.Lock()
-	defer d.mu.Unlock()
+	defer d.unlock()
 
-	if k := d.lookup(n); k != nil {
-		return k.open(), nil
+	k, dir, err := d.get(ctx, root, n, fetchFulfill)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	if k == nil {
+		_, mt, _ := d.readDir(dir)
+		return &dirFile{fs: d, info: dirInfo{name: dir, modtime: mt}}, nil
 	}
+	f, err := k.open()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	f.dir = dir
+	return f, nil
+}
 
-	if k, err := d.fulfill(n); err != nil {
-		return nil, err
-	} else {
-		return k.open(), nil
+func (d *FS) peekFile(ctx context.Context, name string) *File {
+	// opens name only if it is already held by the FS and the Authorizer
+	// allows it
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	if n, err = d.follow(n); err != nil {
+		return nil
 	}
+	if d.authorized(ctx, n) != nil {
+		return nil
+	}
+	k, err := d.fetchKey(ctx, n, fetchCached)
+	if err != nil {
+		return nil
+	}
+	f, err := k.open()
+	if err != nil {
+		return nil
+	}
+	return f
 }
 
 // ReadFile implements [fs.ReadFileFS]. Note that, because ReadFile returns
@@ -172,79 +641,163 @@ func (d *FS) Open(name string) (fs.File, error) {
 // a more efficient route would be getting the File and using [io.WriterTo]
 // via [io.Copy] to (paradoxically) reduce intermediate copies.
 func (d *FS) ReadFile(name string) ([]byte, error) {
+	return d.ReadFileContext(context.Background(), name)
+}
+
+// ReadFileContext is like ReadFile, but ctx is passed along as for
+// OpenContext.
+func (d *FS) ReadFileContext(ctx context.Context, name string) ([]byte, error) {
+	return d.readFileIn(ctx, ".", name)
+}
+
+// readFileIn is ReadFileContext, resolving links within root.
+func (d *FS) readFileIn(ctx context.Context, root, name string) ([]byte, error) {
 	n, err := d.normalize(name)
 	if err != nil {
-		return nil, fmt.Errorf("cannot retrieve key %q: %w", name, err)
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
 	}
 	d.mu.Lock()
-	defer d.mu.Unlock()
+	defer d.unlock()
 
-	if k := d.lookup(n); k != nil {
-		return bytes.Clone(k.bytes), nil
+	k, _, err := d.get(ctx, root, n, fetchFulfill)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
 	}
-
-	if k, err := d.fulfill(n); err != nil {
-		return nil, err
-	} else {
-		return bytes.Clone(k.bytes), nil
+	if k == nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: errIsDir}
+	}
+	b, err := k.content()
+	if err != nil {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: err}
 	}
+	if k.aead == nil && !k.wipe {
+		b = bytes.Clone(b)
+	}
+	return b, nil
 }
 
 // Stat implements [fs.StatFS].
 func (d *FS) Stat(name string) (fs.FileInfo, error) {
+	return d.statIn(".", name)
+}
+
+// statIn is Stat, resolving links within root.
+func (d *FS) statIn(root, name string) (fs.FileInfo, error) {
 	n, err := d.normalize(name)
 	if err != nil {
-		return nil, fmt.Errorf("cannot stat key %q: %w", name, err)
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
 	}
 	d.mu.Lock()
-	defer d.mu.Unlock()
+	defer d.unlock()
 
-	if k := d.lookup(n); k != nil {
-		return &FileStat{k: k}, nil
+	how := fetchCached
+	if d.statFulfills {
+		how = fetchStat
 	}
-
-	if !d.statFulfills {
-		return nil, fs.ErrNotExist
+	k, dir, err := d.get(context.Background(), root, n, how)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+	if k == nil {
+		_, mt, _ := d.readDir(dir)
+		return &dirInfo{name: dir, modtime: mt}, nil
+	}
+	if dir == "" {
+		return k.fileStat(), nil
 	}
+	return &FileStat{k: k, dir: dir}, nil
+}
 
-	if k, err := d.fulfill(n); err != nil {
+// Sub implements [fs.SubFS]. The returned SubFS is a view of d, so it sees keys
+// put into d afterwards and fulfills from d's Fulfillers.
+func (d *FS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	sub, err := d.sub(dir)
+	if err != nil {
 		return nil, err
-	} else {
-		return &FileStat{k: k}, nil
 	}
+	return sub, nil
 }
 
-// Sub implements [fs.SubFS].
-func (d *FS) Sub(dir string) (fs.FS, error) {
-	return &SubFS{p: d, d: dir}, nil
+func (d *FS) sub(dir string) (*SubFS, error) {
+	root, err := d.normalize(dir)
+	if err != nil {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: err}
+	}
+	return &SubFS{p: d, d: dir, root: root}, nil
 }
 
 // Expire removes an item from the FS.
 func (d *FS) Expire(name string) error {
 	n, err := d.normalize(name)
 	if err != nil {
-		return fmt.Errorf("cannot expire key %q: %w", name, err)
+		return &fs.PathError{Op: "expire", Path: name, Err: err}
 	}
 	d.mu.Lock()
-	defer d.mu.Unlock()
-	delete(d.keys, n)
+	defer d.unlock()
+	if err := d.checkSealed("expire key"); err != nil {
+		return &fs.PathError{Op: "expire", Path: name, Err: err}
+	}
+	if k, ok := d.keys.lookup(d.fold(n)); ok && k != nil {
+		d.retire(k)
+		d.emit(context.Background(), EventRemoved, k)
+	}
+	d.keys.remove(d.fold(n))
 	return nil
 }
 
+// ExpirePrefix removes every key beneath the directory prefix, or every key
+// if prefix is "" or ".", in one pass under the lock, and returns the number
+// removed. This invalidates eg all the content generated for one user or
+// build at once. Only the keys beneath prefix are visited.
+func (d *FS) ExpirePrefix(prefix string) (int, error) {
+	dir := "."
+	if prefix != "" && prefix != "." {
+		n, err := d.normalize(prefix)
+		if err != nil {
+			return 0, &fs.PathError{Op: "expireprefix", Path: prefix, Err: err}
+		}
+		dir = d.fold(n)
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	if err := d.checkSealed("expire keys"); err != nil {
+		return 0, &fs.PathError{Op: "expireprefix", Path: prefix, Err: err}
+	}
+	removed := 0
+	for name, k := range d.keys.under(dir) {
+		d.keys.remove(name)
+		if k == nil {
+			continue
+		}
+		d.retire(k)
+		d.emit(context.Background(), EventRemoved, k)
+		removed++
+	}
+	return removed, nil
+}
+
 // FlushExpired scans all items in the FS and removes any that have
 // expired.
 func (d *FS) FlushExpired() error {
-	e := make(map[string]bool, len(d.keys))
-	n := time.Now()
 	d.mu.Lock()
-	for k, kp := range d.keys {
+	defer d.unlock()
+	if err := d.checkSealed("flush expired keys"); err != nil {
+		return err
+	}
+	n := d.now()
+	e := make(map[string]bool, d.keys.len())
+	for k, kp := range d.keys.all() {
 		if kp != nil && kp.expire != nil && n.After(*kp.expire) {
 			e[k] = true
 		}
 	}
 	for k := range e {
-		delete(d.keys, k)
+		d.retire(d.keys.get(k))
+		d.emit(context.Background(), EventExpired, d.keys.get(k))
+		d.keys.remove(k)
 	}
-	d.mu.Unlock()
 	return nil
 }
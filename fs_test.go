@@ -0,0 +1,67 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestFulfillWithTTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(DefaultTTL(time.Hour), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.FulfillWithTTL(nil, 0); err == nil {
+		t.Error("FulfillWithTTL accepted a zero ttl")
+	}
+	d.FulfillWithTTL(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		switch p {
+		case "own":
+			expire := now.Add(time.Second)
+			return []byte(p), nil, &expire, nil
+		case "forever":
+			forever := Forever
+			return []byte(p), nil, &forever, nil
+		}
+		return []byte(p), nil, nil, nil
+	}, time.Minute)
+
+	for name, want := range map[string]time.Time{
+		"short":   now.Add(time.Minute),
+		"own":     now.Add(time.Second),
+		"forever": now.Add(time.Minute),
+	} {
+		if _, err := d.ReadFile(name); err != nil {
+			t.Fatal(err)
+		}
+		if m, err := d.GetMeta(name); err != nil || m.Expire == nil || !m.Expire.Equal(want) {
+			t.Errorf("%s expire = %v, %v; want %v", name, m.Expire, err, want)
+		}
+	}
+}
+
+func TestPathErrors(t *testing.T) {
+	d, err := New(StrictPaths(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const bad = "/bad"
+	calls := map[string]func() error{
+		"open":     func() error { _, err := d.Open(bad); return err },
+		"readfile": func() error { _, err := d.ReadFile(bad); return err },
+		"stat":     func() error { _, err := d.Stat(bad); return err },
+		"readdir":  func() error { _, err := d.ReadDir(bad); return err },
+		"put":      func() error { return d.Put(bad, nil, time.Now(), nil) },
+		"expire":   func() error { return d.Expire(bad) },
+		"readlink": func() error { _, err := d.ReadLink(bad); return err },
+		"readat":   func() error { _, err := d.ReadAt(bad, nil, 0); return err },
+	}
+	for op, call := range calls {
+		var pe *fs.PathError
+		if err := call(); !errors.As(err, &pe) || pe.Op != op || pe.Path != bad || !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("%s: got %#v", op, err)
+		}
+	}
+}
@@ -1,6 +1,64 @@
 package gomemfs
 
-import "errors"
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// options holds the settings of an FS that are changed by an FSOption.
+type options struct {
+	caseInsensitive bool
+	casePreserving  bool
+	statFulfills    bool
+	strictPaths     bool
+	backslashes     bool
+	normalizers     []Normalizer
+
+	tracer      Tracer
+	traceMisses bool
+
+	logger    *slog.Logger
+	logLevels LogLevels
+
+	onExpire OnExpire
+	onEvict  OnEvict
+
+	sealPanics bool
+
+	fallback    fs.FS
+	fallbackTTL time.Duration
+
+	notFound   map[string]string
+	indexFiles []string
+
+	clock          Clock
+	defaultTTL     time.Duration
+	defaultModTime DefaultModTime
+	maxEntryBytes  int64
+	maxKeys        int
+	quotas         map[string]Quota
+	rateLimit      *rateLimiter
+	breaker        CircuitBreaker
+	retry          RetryPolicy
+	authorize      Authorizer
+	keepVersions   int
+	earlyExpiry    float64
+	wipe           bool
+	aead           cipher.AEAD
+	verify         bool
+	arena          *arena
+	pressure       float64
+	accessWindow   time.Duration
+}
+
+var defaultOptions = options{
+	logLevels: DefaultLogLevels,
+}
 
 // An FSOption represents a value that can be passed to gomemfs.New() or FS.Set to
 // modify the behavior of an FS.
@@ -14,16 +72,32 @@ type FSOption interface {
 type CaseInsensitive bool
 
 func (fso CaseInsensitive) applyTo(fs *FS) error {
-	if len(fs.keys) > 0 {
+	if fs.keys.len() > 0 {
 		return errors.New("cannot update case sensitivity with existing keys")
 	}
 	fs.caseInsensitive = bool(fso)
 	return nil
 }
 
+// CasePreserving, if true, causes an FS to match keys case-insensitively like
+// CaseInsensitive, but to keep the casing a key was first put or fulfilled
+// with, and report it from eg FileStat.Name, as macOS and Windows filesystems
+// do. Fulfillers receive names as they were requested. It takes precedence
+// over CaseInsensitive. This option can only be set if the FS is empty.
+type CasePreserving bool
+
+func (fso CasePreserving) applyTo(fs *FS) error {
+	if fs.keys.len() > 0 {
+		return errors.New("cannot update case preservation with existing keys")
+	}
+	fs.casePreserving = bool(fso)
+	return nil
+}
+
 // StatFulfills, if true, will cause an FS to fulfill a missing key on a call to
 // FS.Stat. By default this is disabled. Usually this is undesirable as the
-// content will be discarded.
+// content will be discarded, unless it comes from a StatSource such as one
+// created with ComposeSource, which can describe a key without fetching it.
 type StatFulfills bool
 
 func (fso StatFulfills) applyTo(fs *FS) error {
@@ -31,5 +105,165 @@ func (fso StatFulfills) applyTo(fs *FS) error {
 	return nil
 }
 
+// StrictPaths, if true, causes an FS to reject names that are not valid
+// according to [fs.ValidPath], or that contain a backslash, with
+// [fs.ErrInvalid] wherever a name is passed to it, such as in Put and Open. By
+// default such names are cleaned with [path.Clean] and a leading slash is
+// removed, so eg "/a/../b" is the same key as "b".
+type StrictPaths bool
+
+func (fso StrictPaths) applyTo(fs *FS) error {
+	fs.strictPaths = bool(fso)
+	return nil
+}
+
+// AcceptBackslashes, if true, causes an FS to treat backslashes in names as
+// slashes, so that paths from Windows tooling such as "assets\css\app.css"
+// can be passed to eg Put and Open as they are. Names starting with a drive
+// letter, such as "C:\assets", are rejected with [fs.ErrInvalid]. The
+// conversion happens before StrictPaths is checked.
+type AcceptBackslashes bool
+
+func (fso AcceptBackslashes) applyTo(fs *FS) error {
+	fs.backslashes = bool(fso)
+	return nil
+}
+
+// Clock replaces [time.Now] as the source of the current time for an FS. It is
+// used to decide whether keys have expired and to set the modtime of keys that
+// are fulfilled without one, so tests can simulate time passing without
+// sleeping. Fulfillers that compute an expiry, such as those created with
+// Compose, still use time.Now. A nil Clock restores time.Now.
+type Clock func() time.Time
+
+func (fso Clock) applyTo(fs *FS) error {
+	fs.clock = fso
+	return nil
+}
+
+func (d *FS) now() time.Time {
+	if d.clock != nil {
+		return d.clock()
+	}
+	return time.Now()
+}
+
+// DefaultTTL, if greater than zero, causes keys that are put with a nil expire,
+// or fulfilled with an expire of Forever, to expire that long after they are
+// stored instead of never expiring. Content a Fulfiller returns with a nil
+// expire is then cached for that long too, instead of not at all. Other expire
+// times are unaffected.
+type DefaultTTL time.Duration
+
+func (fso DefaultTTL) applyTo(fs *FS) error {
+	fs.defaultTTL = time.Duration(fso)
+	return nil
+}
+
+// defaultExpire returns expire, or the expire time given by DefaultTTL if
+// expire is nil.
+func (d *FS) defaultExpire(expire *time.Time) *time.Time {
+	if expire != nil || d.defaultTTL <= 0 {
+		return expire
+	}
+	e := d.now().Add(d.defaultTTL)
+	return &e
+}
+
+// DefaultModTime decides the modtime of a key fulfilled by a Fulfiller that
+// returned a nil modtime. By default, and if DefaultModTime is nil, the
+// current time is used, which differs between replicas of a deployment and
+// so between their Last-Modified headers; see FixedModTime.
+type DefaultModTime func(path string) time.Time
+
+func (fso DefaultModTime) applyTo(fs *FS) error {
+	fs.defaultModTime = fso
+	return nil
+}
+
+// FixedModTime returns a DefaultModTime that always returns t, such as the
+// time the program was built. Passing the zero time gives keys a zero modtime,
+// which [net/http.ServeContent] omits from responses.
+func FixedModTime(t time.Time) DefaultModTime {
+	return func(string) time.Time { return t }
+}
+
+// MaxEntryBytes, if greater than zero, limits the size of the content of a
+// key. Putting a larger key fails, and larger content produced by a Fulfiller
+// is discarded, in both cases with a *TooLargeError.
+type MaxEntryBytes int64
+
+func (fso MaxEntryBytes) applyTo(fs *FS) error {
+	fs.maxEntryBytes = int64(fso)
+	return nil
+}
+
+// A TooLargeError is returned, usually wrapped in an [fs.PathError], when
+// content exceeds the limit set with MaxEntryBytes.
+type TooLargeError struct {
+	Name  string
+	Size  int64
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("content of %q is %d bytes, more than the limit of %d", e.Name, e.Size, e.Limit)
+}
+
+// checkSize returns a *TooLargeError if content is too large to be held.
+func (d *FS) checkSize(name string, content []byte) error {
+	if d.maxEntryBytes > 0 && int64(len(content)) > d.maxEntryBytes {
+		return &TooLargeError{Name: name, Size: int64(len(content)), Limit: d.maxEntryBytes}
+	}
+	return nil
+}
+
+// MaxKeys, if greater than zero, limits the number of keys an FS holds. Once
+// the limit is reached, putting or fulfilling a new key fails with ErrFull
+// rather than making room, so that a runaway set of names is noticed instead
+// of hidden. Replacing an existing key is always allowed.
+type MaxKeys int
+
+func (fso MaxKeys) applyTo(fs *FS) error {
+	fs.maxKeys = int(fso)
+	return nil
+}
+
+// ErrFull is returned, usually wrapped in an [fs.PathError], when a key cannot
+// be stored because the limit set with MaxKeys has been reached.
+var ErrFull = errors.New("FS holds the maximum number of keys")
+
+// checkFull returns ErrFull if a new key called name cannot be stored.
+func (d *FS) checkFull(name string) error {
+	// must be called with fs.mu Locked
+	if d.maxKeys <= 0 || d.keys.len() < d.maxKeys || d.lookup(name) != nil {
+		return nil
+	}
+	// expired keys do not count, but may not have been removed yet
+	for n := range d.keys.all() {
+		d.lookup(n)
+	}
+	if d.keys.len() < d.maxKeys {
+		return nil
+	}
+	return ErrFull
+}
+
+// IndexFiles returns an FSOption that causes a missing key to be resolved to
+// the first of names that exists beneath it, so that eg "docs" or "docs/" is
+// served from "docs/index.html". A key resolved this way is reported as a
+// directory by Stat, which allows an FS to be used directly with
+// [net/http.FileServerFS]. Calling IndexFiles with no names disables this.
+func IndexFiles(names ...string) FSOption {
+	return indexFilesOption(slices.Clone(names))
+}
+
+type indexFilesOption []string
+
+func (fso indexFilesOption) applyTo(fs *FS) error {
+	fs.indexFiles = fso
+	return nil
+}
+
 // FUTURE:
 // * IncludeFolders
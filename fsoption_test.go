@@ -0,0 +1,238 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestStrictPaths(t *testing.T) {
+	lax, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lax.Put("/a/../b", []byte("b"), time.Now(), nil)
+	if !lax.Exists("b") {
+		t.Error("name not cleaned without StrictPaths")
+	}
+
+	d, err := New(StrictPaths(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"/b", "a/../b", "a//b", `a\b`, ""} {
+		if err := d.Put(name, nil, time.Now(), nil); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Put(%q) = %v, want fs.ErrInvalid", name, err)
+		}
+		if _, err := d.Open(name); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Open(%q) = %v, want fs.ErrInvalid", name, err)
+		}
+	}
+	if err := d.Put("a/b", nil, time.Now(), nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCasePreserving(t *testing.T) {
+	var requested []string
+	d, err := New(CasePreserving(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		requested = append(requested, p)
+		expire := time.Now().Add(time.Hour)
+		return []byte(p), nil, &expire, nil
+	})
+	d.Put("Docs/README.md", []byte("readme"), time.Now(), nil)
+	if b, err := d.ReadFile("docs/readme.MD"); err != nil || string(b) != "readme" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if fi, err := d.Stat("DOCS/readme.md"); err != nil || fi.Name() != "README.md" {
+		t.Errorf("Stat = %v, %v; want the original casing", fi, err)
+	}
+	d.ReadFile("Img/Logo.PNG")
+	d.ReadFile("img/logo.png")
+	if len(requested) != 1 || requested[0] != "Img/Logo.PNG" {
+		t.Errorf("Fulfiller called with %q", requested)
+	}
+	es, err := d.ReadDir("img")
+	if err != nil || len(es) != 1 || es[0].Name() != "Logo.PNG" {
+		t.Errorf("ReadDir = %v, %v", es, err)
+	}
+	if err := d.Set(CasePreserving(false)); err == nil {
+		t.Error("CasePreserving changed with existing keys")
+	}
+}
+
+func TestAcceptBackslashes(t *testing.T) {
+	d, err := New(AcceptBackslashes(true), StrictPaths(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Put(`assets\css\app.css`, []byte("css"), time.Now(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("assets/css/app.css"); err != nil || string(b) != "css" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if _, err := d.Open(`C:\assets`); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Open of a drive letter = %v, want fs.ErrInvalid", err)
+	}
+	sub, err := d.Sub("assets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := fs.ReadFile(sub, `css\app.css`); err != nil || string(b) != "css" {
+		t.Errorf("SubFS ReadFile = %q, %v", b, err)
+	}
+}
+
+func TestClock(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	soon := now.Add(time.Minute)
+	d.Put("a", []byte("a"), now, &soon)
+	if !d.Exists("a") {
+		t.Fatal("key expired before its time")
+	}
+	now = now.Add(2 * time.Minute)
+	if d.Exists("a") {
+		t.Error("key not expired by the Clock")
+	}
+	if _, err := d.ReadFile("a"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile of an expired key = %v", err)
+	}
+}
+
+func TestDefaultTTL(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(DefaultTTL(time.Hour), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	own := now.Add(time.Minute)
+	d.Put("default", []byte("a"), now, nil)
+	d.Put("own", []byte("a"), now, &own)
+	for name, want := range map[string]time.Time{"default": now.Add(time.Hour), "own": own} {
+		if m, err := d.GetMeta(name); err != nil || m.Expire == nil || !m.Expire.Equal(want) {
+			t.Errorf("%s expire = %v, %v; want %v", name, m.Expire, err, want)
+		}
+	}
+	now = now.Add(2 * time.Hour)
+	if d.Exists("default") {
+		t.Error("key outlived DefaultTTL")
+	}
+}
+
+func TestDefaultModTime(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	built := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	own := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		expire := now.Add(time.Hour)
+		if p == "own" {
+			return []byte(p), &own, &expire, nil
+		}
+		return []byte(p), nil, &expire, nil
+	})
+	modtime := func(name string) time.Time {
+		t.Helper()
+		f, err := d.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		fi, _ := f.Stat()
+		return fi.ModTime()
+	}
+	if mt := modtime("a"); !mt.Equal(now) {
+		t.Errorf("modtime = %v, want the current time", mt)
+	}
+	d.Set(FixedModTime(built))
+	if mt := modtime("b"); !mt.Equal(built) {
+		t.Errorf("modtime = %v, want FixedModTime", mt)
+	}
+	if mt := modtime("own"); !mt.Equal(own) {
+		t.Errorf("modtime = %v, want the Fulfiller's", mt)
+	}
+}
+
+func TestMaxEntryBytes(t *testing.T) {
+	d, err := New(MaxEntryBytes(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tle *TooLargeError
+	if err := d.Put("big", []byte("12345"), time.Now(), nil); !errors.As(err, &tle) || tle.Size != 5 || tle.Limit != 4 {
+		t.Errorf("Put = %v, want a *TooLargeError", err)
+	}
+	if err := d.Put("small", []byte("1234"), time.Now(), nil); err != nil {
+		t.Error(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		forever := Forever
+		return []byte(p), nil, &forever, nil
+	})
+	if _, err := d.ReadFile("fulfilled"); !errors.As(err, &tle) {
+		t.Errorf("ReadFile = %v, want a *TooLargeError", err)
+	}
+	if d.Exists("fulfilled") {
+		t.Error("oversized fulfilled content was kept")
+	}
+}
+
+func TestMaxKeys(t *testing.T) {
+	d, err := New(MaxKeys(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a", []byte("a"), time.Now(), nil)
+	d.Put("b", []byte("b"), time.Now(), nil)
+	if err := d.Put("c", []byte("c"), time.Now(), nil); !errors.Is(err, ErrFull) {
+		t.Errorf("Put = %v, want ErrFull", err)
+	}
+	if err := d.Put("a", []byte("A"), time.Now(), nil); err != nil {
+		t.Errorf("replacing a key = %v", err)
+	}
+	d.Expire("b")
+	if err := d.Put("c", []byte("c"), time.Now(), nil); err != nil {
+		t.Errorf("Put after Expire = %v", err)
+	}
+}
+
+func TestDefaultTTLFulfilled(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(DefaultTTL(time.Hour), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls++
+		return []byte("a"), nil, nil, nil
+	})
+	for range 2 {
+		if _, err := d.ReadFile("a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fulfilled %d times, want content with a nil expire cached", calls)
+	}
+	if m, err := d.GetMeta("a"); err != nil || m.Expire == nil || !m.Expire.Equal(now.Add(time.Hour)) {
+		t.Errorf("expire = %v, %v; want DefaultTTL", m.Expire, err)
+	}
+	now = now.Add(2 * time.Hour)
+	if d.Exists("a") {
+		t.Error("key outlived DefaultTTL")
+	}
+}
@@ -0,0 +1,203 @@
+//go:build linux || darwin
+
+// Package fuse mounts a gomemfs FS as a read-only filesystem with FUSE, so
+// that its keys can be inspected with ordinary tools such as ls, cat and diff
+// while developing or debugging.
+package fuse
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"syscall"
+	"time"
+
+	fusefs "github.com/hanwen/go-fuse/v2/fs"
+	gofuse "github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/ironiridis/gomemfs"
+)
+
+// timeout is how long the kernel may cache names and attributes. It is short,
+// as keys expire and are replaced.
+const timeout = time.Second
+
+// Mount mounts d read-only at the directory dir and returns the server, whose
+// Unmount method should be called when done, and whose Wait method blocks
+// until then. Looking up a name that is not held fulfills it, so generated
+// keys can be read by path, but directory listings show only the keys held,
+// as FS.ReadDir does. Symbolic links created with Symlink are shown as links.
+func Mount(d *gomemfs.FS, dir string) (*gofuse.Server, error) {
+	t := timeout
+	return fusefs.Mount(dir, &node{d: d, name: "."}, &fusefs.Options{
+		EntryTimeout: &t,
+		AttrTimeout:  &t,
+		MountOptions: gofuse.MountOptions{
+			FsName:  "gomemfs",
+			Name:    "gomemfs",
+			Options: []string{"ro"},
+		},
+	})
+}
+
+// A node is a key or directory of the FS, by name.
+type node struct {
+	fusefs.Inode
+	d    *gomemfs.FS
+	name string
+}
+
+var (
+	_ fusefs.NodeLookuper   = (*node)(nil)
+	_ fusefs.NodeReaddirer  = (*node)(nil)
+	_ fusefs.NodeGetattrer  = (*node)(nil)
+	_ fusefs.NodeOpener     = (*node)(nil)
+	_ fusefs.NodeReader     = (*node)(nil)
+	_ fusefs.NodeReadlinker = (*node)(nil)
+)
+
+// stat describes the node, fulfilling it if needed.
+func (n *node) stat(ctx context.Context) (fs.FileInfo, error) {
+	fi, err := n.d.Lstat(n.name)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return fi, err
+	}
+	f, err := n.d.OpenContext(ctx, n.name)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return n.d.Lstat(n.name)
+}
+
+// Lookup implements fusefs.NodeLookuper.
+func (n *node) Lookup(ctx context.Context, name string, out *gofuse.EntryOut) (*fusefs.Inode, syscall.Errno) {
+	c := &node{d: n.d, name: path.Join(n.name, name)}
+	fi, err := c.stat(ctx)
+	if err != nil {
+		return nil, errno(err)
+	}
+	fill(&out.Attr, fi)
+	return n.NewInode(ctx, c, fusefs.StableAttr{Mode: out.Attr.Mode & syscall.S_IFMT}), 0
+}
+
+// Getattr implements fusefs.NodeGetattrer.
+func (n *node) Getattr(ctx context.Context, _ fusefs.FileHandle, out *gofuse.AttrOut) syscall.Errno {
+	fi, err := n.stat(ctx)
+	if err != nil {
+		return errno(err)
+	}
+	fill(&out.Attr, fi)
+	return 0
+}
+
+// Readdir implements fusefs.NodeReaddirer.
+func (n *node) Readdir(ctx context.Context) (fusefs.DirStream, syscall.Errno) {
+	des, err := n.d.ReadDir(n.name)
+	if err != nil {
+		return nil, errno(err)
+	}
+	list := make([]gofuse.DirEntry, 0, len(des))
+	for _, de := range des {
+		list = append(list, gofuse.DirEntry{Name: de.Name(), Mode: mode(de.Type()) & syscall.S_IFMT})
+	}
+	return fusefs.NewListDirStream(list), 0
+}
+
+// Open implements fusefs.NodeOpener. The content is read once, so the handle
+// is unaffected by the key later being replaced or expiring.
+func (n *node) Open(ctx context.Context, flags uint32) (fusefs.FileHandle, uint32, syscall.Errno) {
+	if flags&(syscall.O_WRONLY|syscall.O_RDWR) != 0 {
+		return nil, 0, syscall.EROFS
+	}
+	f, err := n.d.OpenContext(ctx, n.name)
+	if err != nil {
+		return nil, 0, errno(err)
+	}
+	defer f.Close()
+	buf, err := io.ReadAll(f)
+	if err != nil {
+		return nil, 0, errno(err)
+	}
+	return &handle{buf}, gofuse.FOPEN_KEEP_CACHE, 0
+}
+
+// A handle holds the content of an open key.
+type handle struct {
+	content []byte
+}
+
+// Read implements fusefs.NodeReader.
+func (n *node) Read(ctx context.Context, fh fusefs.FileHandle, dest []byte, off int64) (gofuse.ReadResult, syscall.Errno) {
+	h, ok := fh.(*handle)
+	if !ok {
+		return nil, syscall.EBADF
+	}
+	if off >= int64(len(h.content)) {
+		return gofuse.ReadResultData(nil), 0
+	}
+	end := min(off+int64(len(dest)), int64(len(h.content)))
+	return gofuse.ReadResultData(h.content[off:end]), 0
+}
+
+// Readlink implements fusefs.NodeReadlinker.
+func (n *node) Readlink(ctx context.Context) ([]byte, syscall.Errno) {
+	target, err := n.d.ReadLink(n.name)
+	if err != nil {
+		return nil, errno(err)
+	}
+	return []byte(target), 0
+}
+
+// fill sets the attributes of a from fi.
+func fill(a *gofuse.Attr, fi fs.FileInfo) {
+	a.Mode = mode(fi.Mode())
+	a.Size = uint64(fi.Size())
+	mt := fi.ModTime()
+	a.SetTimes(nil, &mt, nil)
+	a.Nlink = 1
+	a.Owner = *gofuse.CurrentOwner()
+}
+
+// mode returns the FUSE mode for m, with read permission for everyone unless
+// m has permission bits of its own.
+func mode(m fs.FileMode) uint32 {
+	var t uint32
+	perm := uint32(m.Perm())
+	switch {
+	case m.IsDir():
+		t = syscall.S_IFDIR
+		if perm == 0 {
+			perm = 0o555
+		}
+	case m&fs.ModeSymlink != 0:
+		t, perm = syscall.S_IFLNK, 0o777
+	default:
+		t = syscall.S_IFREG
+		if perm == 0 {
+			perm = 0o444
+		}
+	}
+	// the filesystem is read-only
+	return t | perm&^0o222
+}
+
+// errno returns the errno best describing err.
+func errno(err error) syscall.Errno {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return syscall.ENOENT
+	case errors.Is(err, fs.ErrPermission):
+		return syscall.EACCES
+	case errors.Is(err, fs.ErrInvalid):
+		return syscall.EINVAL
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return syscall.EINTR
+	}
+	var e syscall.Errno
+	if errors.As(err, &e) {
+		return e
+	}
+	return syscall.EIO
+}
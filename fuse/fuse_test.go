@@ -0,0 +1,112 @@
+//go:build linux || darwin
+
+package fuse
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"syscall"
+	"testing"
+	"time"
+
+	fusefs "github.com/hanwen/go-fuse/v2/fs"
+	gofuse "github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/ironiridis/gomemfs"
+)
+
+func newRoot(t *testing.T) *node {
+	d, err := gomemfs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("docs/a.txt", []byte("hello"), time.Now(), nil, gomemfs.Mode(0o640))
+	d.Symlink("docs/a.txt", "latest")
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		if p == "gen/b.txt" {
+			expire := time.Now().Add(time.Hour)
+			return []byte("generated"), nil, &expire, nil
+		}
+		return nil, nil, nil, nil
+	})
+	root := &node{d: d, name: "."}
+	// attach the root to a tree, without mounting it, so that Lookup works
+	fusefs.NewNodeFS(root, &fusefs.Options{})
+	return root
+}
+
+func TestNode(t *testing.T) {
+	root := newRoot(t)
+	ctx := context.Background()
+
+	var out gofuse.EntryOut
+	if _, e := root.Lookup(ctx, "docs", &out); e != 0 || out.Attr.Mode != syscall.S_IFDIR|0o555 {
+		t.Errorf("Lookup(docs) = %o, %v", out.Attr.Mode, e)
+	}
+	if _, e := root.Lookup(ctx, "missing", &out); e != syscall.ENOENT {
+		t.Errorf("Lookup(missing) = %v, want ENOENT", e)
+	}
+
+	gen := &node{d: root.d, name: "gen/b.txt"}
+	var attr gofuse.AttrOut
+	if e := gen.Getattr(ctx, nil, &attr); e != 0 || attr.Size != uint64(len("generated")) {
+		t.Errorf("Getattr of an unfulfilled key = %d, %v", attr.Size, e)
+	}
+	a := &node{d: root.d, name: "docs/a.txt"}
+	if e := a.Getattr(ctx, nil, &attr); e != 0 || attr.Mode != syscall.S_IFREG|0o440 {
+		t.Errorf("Getattr mode = %o, %v; want read-only permission bits", attr.Mode, e)
+	}
+
+	if _, _, e := a.Open(ctx, syscall.O_RDWR); e != syscall.EROFS {
+		t.Errorf("Open for writing = %v, want EROFS", e)
+	}
+	fh, _, e := a.Open(ctx, syscall.O_RDONLY)
+	if e != 0 {
+		t.Fatal(e)
+	}
+	res, e := a.Read(ctx, fh, make([]byte, 3), 1)
+	if b, _ := res.Bytes(nil); e != 0 || string(b) != "ell" {
+		t.Errorf("Read = %q, %v", b, e)
+	}
+	res, _ = a.Read(ctx, fh, make([]byte, 3), 10)
+	if b, _ := res.Bytes(nil); len(b) != 0 {
+		t.Errorf("Read past the end = %q", b)
+	}
+
+	link := &node{d: root.d, name: "latest"}
+	if b, e := link.Readlink(ctx); e != 0 || string(b) != "docs/a.txt" {
+		t.Errorf("Readlink = %q, %v", b, e)
+	}
+	if e := link.Getattr(ctx, nil, &attr); e != 0 || attr.Mode&syscall.S_IFMT != syscall.S_IFLNK {
+		t.Errorf("Getattr of a link = %o, %v", attr.Mode, e)
+	}
+
+	ds, e := root.Readdir(ctx)
+	if e != 0 {
+		t.Fatal(e)
+	}
+	var names []string
+	for ds.HasNext() {
+		de, _ := ds.Next()
+		names = append(names, de.Name)
+	}
+	if len(names) != 3 || names[0] != "docs" || names[1] != "gen" || names[2] != "latest" {
+		t.Errorf("Readdir = %q", names)
+	}
+}
+
+func TestErrno(t *testing.T) {
+	for err, want := range map[error]syscall.Errno{
+		fs.ErrNotExist:                    syscall.ENOENT,
+		fs.ErrPermission:                  syscall.EACCES,
+		context.Canceled:                  syscall.EINTR,
+		syscall.ENOSPC:                    syscall.ENOSPC,
+		errors.New("other"):               syscall.EIO,
+		gomemfs.ErrRateLimited:            syscall.EIO,
+		&fs.PathError{Err: fs.ErrInvalid}: syscall.EINVAL,
+	} {
+		if got := errno(err); got != want {
+			t.Errorf("errno(%v) = %v, want %v", err, got, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package gomemfs
+
+import (
+	"context"
+	"io/fs"
+)
+
+// A Future is the pending result of FS.OpenAsync.
+type Future struct {
+	done chan struct{}
+	f    fs.File
+	err  error
+}
+
+// OpenAsync starts opening name, as with Open, in a new goroutine and returns
+// a Future for the result. This allows eg a request handler to start
+// fulfilling several keys and then collect them, rather than opening each in
+// turn.
+func (d *FS) OpenAsync(name string) *Future {
+	fu := &Future{done: make(chan struct{})}
+	go func() {
+		defer close(fu.done)
+		fu.f, fu.err = d.OpenContext(context.Background(), name)
+	}()
+	return fu
+}
+
+// Done returns a channel that is closed when the result is ready.
+func (fu *Future) Done() <-chan struct{} {
+	return fu.done
+}
+
+// Result waits for the result to be ready and returns it. It may be called
+// any number of times, always returning the same File, which the caller
+// should close once.
+func (fu *Future) Result() (fs.File, error) {
+	<-fu.done
+	return fu.f, fu.err
+}
@@ -0,0 +1,53 @@
+package gomemfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOpenAsync(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var started atomic.Int32
+	release := make(chan struct{})
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		if p == "missing" {
+			return nil, nil, nil, nil
+		}
+		started.Add(1)
+		<-release
+		return []byte(p), nil, nil, nil
+	})
+	a, b := d.OpenAsync("a"), d.OpenAsync("b")
+	for started.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-a.Done():
+		t.Fatal("Future done before its fulfillment")
+	default:
+	}
+	close(release)
+	for _, fu := range []*Future{a, b} {
+		f, err := fu.Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := fu.Result(); again != f {
+			t.Error("Result returned a different File")
+		}
+		if got, _ := io.ReadAll(f); len(got) != 1 {
+			t.Errorf("read %q", got)
+		}
+		f.Close()
+	}
+	if _, err := d.OpenAsync("missing").Result(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Result = %v, want fs.ErrNotExist", err)
+	}
+}
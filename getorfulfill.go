@@ -0,0 +1,83 @@
+package gomemfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+)
+
+// GetOrFulfill returns the content of key name if it is held, and otherwise
+// calls f to fulfill it, caching the result as if a Source added with
+// FulfillWith had produced it. The Sources of the FS are not consulted. This
+// suits content whose generation is only known at the call site; concurrent
+// callers for the same name wait for a single call of f, and a fulfillment of
+// name already in progress is waited for, and f is only called if that does
+// not produce content. If f returns nil content, the error wraps
+// [fs.ErrNotExist].
+func (d *FS) GetOrFulfill(ctx context.Context, name string, f Fulfiller) ([]byte, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "getorfulfill", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+
+	if n, err = d.follow(n); err != nil {
+		return nil, &fs.PathError{Op: "getorfulfill", Path: name, Err: err}
+	}
+	if err := d.authorized(ctx, n); err != nil {
+		return nil, &fs.PathError{Op: "getorfulfill", Path: name, Err: err}
+	}
+	k, err := d.fetchKey(ctx, n, fetchCached)
+	if errors.Is(err, fs.ErrNotExist) {
+		k, err = d.fulfillFrom(ctx, n, f)
+	}
+	if err != nil {
+		return nil, &fs.PathError{Op: "getorfulfill", Path: name, Err: err}
+	}
+	b, err := k.content()
+	if err != nil {
+		return nil, &fs.PathError{Op: "getorfulfill", Path: name, Err: err}
+	}
+	if k.aead == nil && !k.wipe {
+		b = bytes.Clone(b)
+	}
+	return b, nil
+}
+
+// fulfillFrom fulfills name from src alone.
+func (d *FS) fulfillFrom(ctx context.Context, name string, src Source) (k *key, err error) {
+	// must be called with fs.mu Locked, which is released while src runs
+	ctx, err = d.enter(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for f := d.flights[d.fold(name)]; f != nil && shared(ctx); f = d.flights[d.fold(name)] {
+		k, err := d.await(ctx, f)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return k, err
+		}
+		// the Sources of the FS did not have name, but src may
+		if k := d.lookup(name); k != nil {
+			return k, nil
+		}
+	}
+	f := d.takeoff(ctx, name)
+	defer func() { d.land(name, f, k, err) }()
+	held := d.keys.get(d.fold(name))
+
+	var content []byte
+	var modtime, expire *time.Time
+	start := time.Now()
+	retry := d.retry
+	d.unlocked(func() { content, modtime, expire, err = retry.fulfill(ctx, src, name) })
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, fs.ErrNotExist
+	}
+	return d.keep(ctx, name, held, fulfilled{content, modtime, expire, src, -1, time.Since(start)})
+}
@@ -0,0 +1,77 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrFulfill(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls atomic.Int32
+	f := Fulfiller(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		e := Forever
+		return []byte("v:" + p), nil, &e, nil
+	})
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if b, err := d.GetOrFulfill(context.Background(), "/a/b", f); err != nil || string(b) != "v:a/b" {
+				t.Errorf("GetOrFulfill = %q, %v", b, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("f called %d times, want 1", n)
+	}
+	if b, err := d.ReadFile("a/b"); err != nil || string(b) != "v:a/b" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+
+	none := Fulfiller(func(string) ([]byte, *time.Time, *time.Time, error) { return nil, nil, nil, nil })
+	if _, err := d.GetOrFulfill(context.Background(), "x", none); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("GetOrFulfill with nil content = %v", err)
+	}
+}
+
+func TestGetOrFulfillAfterMissingFlight(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	started, release := make(chan struct{}), make(chan struct{})
+	d.FulfillWith(func(string) ([]byte, *time.Time, *time.Time, error) {
+		close(started)
+		<-release
+		return nil, nil, nil, nil
+	})
+	go d.ReadFile("k")
+	<-started
+	done := make(chan error)
+	go func() {
+		b, err := d.GetOrFulfill(context.Background(), "k", func(string) ([]byte, *time.Time, *time.Time, error) {
+			return []byte("mine"), nil, nil, nil
+		})
+		if err == nil && string(b) != "mine" {
+			err = errors.New("wrong content " + string(b))
+		}
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
@@ -1,3 +1,12 @@
 module github.com/ironiridis/gomemfs
 
 go 1.24.5
+
+require (
+	github.com/go-git/go-billy/v5 v5.6.2
+	github.com/hanwen/go-fuse/v2 v2.11.0
+	github.com/spf13/afero v1.15.0
+	golang.org/x/text v0.28.0
+)
+
+require golang.org/x/sys v0.29.0 // indirect
@@ -0,0 +1,48 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// A HealthChecker is a Source (or the fs.FS given to Fallback) that can check
+// that whatever backs it, such as a remote service, is reachable.
+// CheckHealth returns nil if it is.
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// CheckHealth calls CheckHealth on every Source and Fallback of the FS that
+// implements HealthChecker, in turn, and returns the errors of those that
+// failed joined with [errors.Join], or nil if none did. It is meant for
+// readiness probes. The FS is not locked while checking.
+func (d *FS) CheckHealth(ctx context.Context) error {
+	d.mu.Lock()
+	callbacks, fallback := d.callbacks, d.fallback
+	d.unlock()
+
+	var errs []error
+	for idx, s := range callbacks {
+		if hc, ok := s.(HealthChecker); ok {
+			if err := hc.CheckHealth(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("fulfiller %d (%s) is unhealthy: %w", idx, fulfillerName(s), err))
+			}
+		}
+	}
+	if hc, ok := fallback.(HealthChecker); ok {
+		if err := hc.CheckHealth(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("fallback %T is unhealthy: %w", fallback, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkHealth checks s if it is a HealthChecker, for Sources that wrap
+// another.
+func checkHealth(ctx context.Context, s Source) error {
+	if hc, ok := s.(HealthChecker); ok {
+		return hc.CheckHealth(ctx)
+	}
+	return nil
+}
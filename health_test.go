@@ -0,0 +1,43 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// probedSource is a Source whose CheckHealth returns err.
+type probedSource struct {
+	err error
+}
+
+func (s *probedSource) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return nil, nil, nil, nil
+}
+
+func (s *probedSource) CheckHealth(ctx context.Context) error {
+	return s.err
+}
+
+func TestCheckHealth(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := errors.New("unreachable")
+	healthy, unhealthy := &probedSource{}, &probedSource{err: down}
+	d.FulfillWith(func(string) ([]byte, *time.Time, *time.Time, error) { return nil, nil, nil, nil })
+	d.FulfillFrom(healthy)
+	if err := d.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth = %v, want nil", err)
+	}
+	d.FulfillFrom(Retry(RateLimited(unhealthy, 1, 1), RetryPolicy{}))
+	if err := d.CheckHealth(context.Background()); !errors.Is(err, down) {
+		t.Errorf("CheckHealth = %v, want the wrapped Source's error", err)
+	}
+	unhealthy.err = nil
+	if err := d.CheckHealth(context.Background()); err != nil {
+		t.Errorf("CheckHealth after recovery = %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package gomemfs
+
+import (
+	"encoding/hex"
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// HTTPFileSystem returns the FS as an [http.FileSystem] for use with
+// [http.FileServer]. It is equivalent to http.FS(d): files support Seek for
+// range requests and directories support Readdir for listings.
+func (d *FS) HTTPFileSystem() http.FileSystem {
+	return http.FS(d)
+}
+
+// A Handler serves the keys of an FS over HTTP. It is created with
+// FileServer.
+type Handler struct {
+	fs      *FS
+	listing *template.Template
+}
+
+// A HandlerOption changes the behavior of a Handler created with FileServer.
+type HandlerOption interface {
+	applyToHandler(*Handler)
+}
+
+// FileServer returns a Handler that serves the keys of d, using the request
+// path (without its leading slash) as the name of the key. Keys are fulfilled
+// as needed. Responses carry a strong ETag derived from the SHA-256 digest of
+// the content and a Last-Modified header from its modtime, and conditional
+// and range requests are handled by [http.ServeContent]. Use
+// [http.StripPrefix] to serve the FS below a path prefix.
+//
+// If the request accepts it, a precompressed variant of the key stored under
+// the same name with a ".br" (brotli) or ".gz" (gzip) suffix is served instead,
+// with the matching Content-Encoding. Variants are only used if they are
+// already held by the FS, eg by Put; they are never fulfilled on demand.
+//
+// Keys created with Redirect are served as redirects.
+func FileServer(d *FS, o ...HandlerOption) *Handler {
+	h := &Handler{fs: d}
+	for _, opt := range o {
+		opt.applyToHandler(h)
+	}
+	return h
+}
+
+// ServeHTTP implements [http.Handler].
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	key := strings.Trim(r.URL.Path, "/")
+	if key == "" {
+		key = "."
+	}
+	f, err := h.fs.OpenContext(r.Context(), key)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	defer f.Close()
+
+	mf, ok := f.(*File)
+	if !ok {
+		// a directory without an index file
+		h.serveListing(w, r, key, f)
+		return
+	}
+	if mf.k.redirect != "" {
+		http.Redirect(w, r, mf.k.redirect, mf.k.redirectCode)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+	name := path.Base(mf.k.name)
+	if v, enc := h.fs.variant(r, mf.k.name); v != nil {
+		defer v.Close()
+		w.Header().Set("Content-Encoding", enc)
+		mf = v
+	}
+
+	sum := mf.k.sum()
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+	http.ServeContent(w, r, name, mf.k.modtime, mf)
+}
+
+// encodings lists the content codings a Handler can serve, in order of
+// preference, along with the suffix of the key holding that variant.
+var encodings = []struct {
+	coding string
+	suffix string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// variant returns the best precompressed variant of key name acceptable to
+// r, along with its content coding. Variants are only served if they are
+// already held by the FS; they are never fulfilled.
+func (d *FS) variant(r *http.Request, name string) (*File, string) {
+	accept := acceptEncodings(r.Header.Values("Accept-Encoding"))
+	if len(accept) == 0 {
+		return nil, ""
+	}
+	var best *File
+	var bestCoding string
+	var bestQ float64
+	for _, e := range encodings {
+		q, ok := accept[e.coding]
+		if !ok {
+			q, ok = accept["*"]
+		}
+		if !ok || q <= bestQ {
+			continue
+		}
+		if f := d.peekFile(r.Context(), name+e.suffix); f != nil {
+			if best != nil {
+				best.Close()
+			}
+			best, bestCoding, bestQ = f, e.coding, q
+		}
+	}
+	return best, bestCoding
+}
+
+// acceptEncodings parses Accept-Encoding header values into a map of content
+// coding to quality value.
+func acceptEncodings(values []string) map[string]float64 {
+	accept := make(map[string]float64)
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			coding, params, _ := strings.Cut(part, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding == "" {
+				continue
+			}
+			q := 1.0
+			for _, p := range strings.Split(params, ";") {
+				k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+				if strings.EqualFold(k, "q") {
+					if f, err := strconv.ParseFloat(v, 64); err == nil {
+						q = f
+					}
+				}
+			}
+			accept[coding] = q
+		}
+	}
+	return accept
+}
+
+func httpError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, fs.ErrInvalid):
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	case errors.Is(err, fs.ErrNotExist):
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,104 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// get serves a GET request for target from h, with the given headers.
+func get(h http.Handler, target string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestFileServer(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Put("hello.txt", []byte("hello, world"), mt, nil)
+	h := FileServer(d)
+
+	w := get(h, "/hello.txt")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || w.Body.String() != "hello, world" || etag == "" {
+		t.Fatalf("GET = %d %q, ETag %q", w.Code, w.Body, etag)
+	}
+	if lm := w.Header().Get("Last-Modified"); lm != mt.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q", lm)
+	}
+	if w := get(h, "/hello.txt", "If-None-Match", etag); w.Code != http.StatusNotModified {
+		t.Errorf("conditional GET = %d", w.Code)
+	}
+	if w := get(h, "/hello.txt", "Range", "bytes=7-11"); w.Code != http.StatusPartialContent || w.Body.String() != "world" {
+		t.Errorf("range GET = %d %q", w.Code, w.Body)
+	}
+	if w := get(h, "/missing"); w.Code != http.StatusNotFound {
+		t.Errorf("GET of a missing key = %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("PUT", "/hello.txt", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("PUT = %d", w.Code)
+	}
+}
+
+func TestFileServerVariants(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("app.js", []byte("plain"), time.Now(), nil)
+	d.Put("app.js.gz", []byte("gzipped"), time.Now(), nil)
+	d.Put("app.js.br", []byte("brotli"), time.Now(), nil)
+	h := FileServer(d)
+
+	for accept, want := range map[string]string{
+		"":                   "plain",
+		"gzip":               "gzipped",
+		"gzip, br":           "brotli",
+		"br;q=0.5, gzip":     "gzipped",
+		"identity":           "plain",
+		"*":                  "brotli",
+		"br;q=0, gzip;q=0.1": "gzipped",
+	} {
+		w := get(h, "/app.js", "Accept-Encoding", accept)
+		if w.Body.String() != want {
+			t.Errorf("Accept-Encoding %q: got %q, want %q", accept, w.Body, want)
+		}
+		if want != "plain" && w.Header().Get("Content-Encoding") == "" {
+			t.Errorf("Accept-Encoding %q: no Content-Encoding", accept)
+		}
+		if w.Header().Get("Content-Type") != "text/javascript; charset=utf-8" {
+			t.Errorf("Accept-Encoding %q: Content-Type %q", accept, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestFileServerVariantsAuthorized(t *testing.T) {
+	d, err := New(Authorizer(func(ctx context.Context, name string) error {
+		if name == "app.js.br" {
+			return errors.New("denied")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("app.js", []byte("plain"), time.Now(), nil)
+	d.Put("app.js.gz", []byte("gzipped"), time.Now(), nil)
+	d.Put("app.js.br", []byte("brotli"), time.Now(), nil)
+	if w := get(FileServer(d), "/app.js", "Accept-Encoding", "gzip, br"); w.Body.String() != "gzipped" {
+		t.Errorf("got %q, want the gzip variant", w.Body)
+	}
+}
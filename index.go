@@ -0,0 +1,171 @@
+package gomemfs
+
+import (
+	"iter"
+	"strings"
+)
+
+// keyIndex holds the keys of an FS by folded name. A map gives constant-time
+// lookups, while a tree of path elements makes visiting the keys beneath a
+// directory, eg for ReadDir, proportional to their number rather than to the
+// number of keys in the FS. The zero value is empty and ready to use.
+type keyIndex struct {
+	m    map[string]*key
+	root keyNode
+}
+
+// A keyNode is a directory, or key, in the tree of a keyIndex.
+type keyNode struct {
+	children map[string]*keyNode
+
+	// name is the folded name of the key held at this node, if held is set.
+	name string
+	held bool
+}
+
+// get returns the key held under f, or nil.
+func (x *keyIndex) get(f string) *key {
+	return x.m[f]
+}
+
+// lookup is like get, but also reports whether f is held at all.
+func (x *keyIndex) lookup(f string) (*key, bool) {
+	k, ok := x.m[f]
+	return k, ok
+}
+
+// len returns the number of keys held.
+func (x *keyIndex) len() int {
+	return len(x.m)
+}
+
+// set holds k under f, replacing any key held under it.
+func (x *keyIndex) set(f string, k *key) {
+	if x.m == nil {
+		x.m = make(map[string]*key)
+	}
+	if _, ok := x.m[f]; !ok {
+		n := &x.root
+		for elem := range elems(f) {
+			c := n.children[elem]
+			if c == nil {
+				if n.children == nil {
+					n.children = make(map[string]*keyNode)
+				}
+				c = &keyNode{}
+				n.children[elem] = c
+			}
+			n = c
+		}
+		n.name, n.held = f, true
+	}
+	x.m[f] = k
+}
+
+// remove removes the key held under f, if any.
+func (x *keyIndex) remove(f string) {
+	if _, ok := x.m[f]; !ok {
+		return
+	}
+	delete(x.m, f)
+	type step struct {
+		parent *keyNode
+		elem   string
+	}
+	var steps []step
+	n := &x.root
+	for elem := range elems(f) {
+		steps = append(steps, step{n, elem})
+		n = n.children[elem]
+	}
+	n.held = false
+	// prune the nodes that no longer lead to a key
+	for i := len(steps) - 1; i >= 0; i-- {
+		s := steps[i]
+		if c := s.parent.children[s.elem]; c.held || len(c.children) > 0 {
+			break
+		}
+		delete(s.parent.children, s.elem)
+	}
+}
+
+// all returns an iterator over every key held and its folded name.
+func (x *keyIndex) all() iter.Seq2[string, *key] {
+	return func(yield func(string, *key) bool) {
+		for f, k := range x.m {
+			if !yield(f, k) {
+				return
+			}
+		}
+	}
+}
+
+// under returns an iterator over the keys held beneath the folded directory
+// name dir, and their folded names. If dir is "" or ".", every key is
+// visited. Keys may be removed while iterating.
+func (x *keyIndex) under(dir string) iter.Seq2[string, *key] {
+	return func(yield func(string, *key) bool) {
+		n := &x.root
+		if dir = strings.TrimSuffix(dir, "/"); dir != "" && dir != "." {
+			for elem := range elems(dir) {
+				if n = n.children[elem]; n == nil {
+					return
+				}
+			}
+		}
+		for _, c := range n.children {
+			if !c.walk(x, yield) {
+				return
+			}
+		}
+	}
+}
+
+// tree returns an iterator over the folded names held in the tree, whether or
+// not the map holds them, so that FS.Check can compare the two.
+func (x *keyIndex) tree() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var walk func(n *keyNode) bool
+		walk = func(n *keyNode) bool {
+			if n.held && !yield(n.name) {
+				return false
+			}
+			for _, c := range n.children {
+				if !walk(c) {
+					return false
+				}
+			}
+			return true
+		}
+		walk(&x.root)
+	}
+}
+
+// walk yields the keys held at or beneath n, stopping early if yield returns
+// false, in which case walk does too.
+func (n *keyNode) walk(x *keyIndex, yield func(string, *key) bool) bool {
+	if n.held {
+		if k, ok := x.m[n.name]; ok && !yield(n.name, k) {
+			return false
+		}
+	}
+	for _, c := range n.children {
+		if !c.walk(x, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// elems returns an iterator over the slash-separated elements of name.
+func elems(name string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for {
+			elem, rest, found := strings.Cut(name, "/")
+			if !yield(elem) || !found {
+				return
+			}
+			name = rest
+		}
+	}
+}
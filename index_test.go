@@ -0,0 +1,128 @@
+package gomemfs
+
+import (
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestKeyIndex(t *testing.T) {
+	var x keyIndex
+	for _, f := range []string{"a", "a/b", "a/c/d", "ab", "e"} {
+		x.set(f, &key{name: f})
+	}
+	under := func(dir string) []string {
+		return slices.Sorted(maps.Keys(maps.Collect(x.under(dir))))
+	}
+	if got := under("a"); !slices.Equal(got, []string{"a/b", "a/c/d"}) {
+		t.Errorf("under(a) = %v", got)
+	}
+	if got := under("."); len(got) != 5 {
+		t.Errorf("under(.) = %v", got)
+	}
+	x.remove("a/c/d")
+	if x.get("a/c/d") != nil || x.len() != 4 {
+		t.Error("a/c/d not removed")
+	}
+	if _, ok := x.root.children["a"].children["c"]; ok {
+		t.Error("empty directory node not pruned")
+	}
+	if got := under("a/c"); len(got) != 0 {
+		t.Errorf("under(a/c) = %v", got)
+	}
+	x.remove("a")
+	if got := under("a"); !slices.Equal(got, []string{"a/b"}) {
+		t.Errorf("under(a) after removing a = %v", got)
+	}
+}
+
+func TestPrefixOperations(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	d.Put("users/1/a", []byte("aa"), now, nil)
+	d.Put("users/1/b", []byte("b"), now.Add(-time.Hour), nil)
+	d.Put("users/10/a", []byte("a"), now, nil)
+	d.Put("users/2", []byte("a"), now, nil)
+
+	s, err := d.StatPrefix("users/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Keys != 2 || s.Bytes != 3 || !s.Oldest.Equal(now.Add(-time.Hour)) || !s.Newest.Equal(now) {
+		t.Errorf("StatPrefix = %+v", s)
+	}
+	if n, err := d.ExpirePrefix("users/1"); err != nil || n != 2 {
+		t.Errorf("ExpirePrefix = %d, %v; want 2", n, err)
+	}
+	if d.Exists("users/1/a") || !d.Exists("users/10/a") || !d.Exists("users/2") {
+		t.Error("ExpirePrefix removed the wrong keys")
+	}
+	es, err := d.ReadDir("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(es) != 2 || es[0].Name() != "10" || es[1].Name() != "2" {
+		t.Errorf("ReadDir = %v", es)
+	}
+}
+
+func TestStatPrefix(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	soon, later, past := now.Add(time.Minute), now.Add(time.Hour), now.Add(-time.Second)
+	d.Put("a/1", []byte("1"), now, &later)
+	d.Put("a/2", []byte("22"), now, &soon)
+	d.Put("b", []byte("333"), now.Add(-time.Hour), nil)
+	d.Put("a/gone", []byte("x"), now, &past)
+
+	s, err := d.StatPrefix("a")
+	if err != nil || s.Keys != 2 || s.Bytes != 3 || !s.SoonestExpiry.Equal(soon) {
+		t.Errorf("StatPrefix(a) = %+v, %v", s, err)
+	}
+	for _, prefix := range []string{"", "."} {
+		if s, err := d.StatPrefix(prefix); err != nil || s.Keys != 3 || !s.Oldest.Equal(now.Add(-time.Hour)) {
+			t.Errorf("StatPrefix(%q) = %+v, %v", prefix, s, err)
+		}
+	}
+	if s, err := d.StatPrefix("none"); err != nil || s.Keys != 0 || !s.Oldest.IsZero() || !s.SoonestExpiry.IsZero() {
+		t.Errorf("StatPrefix(none) = %+v, %v", s, err)
+	}
+}
+
+func TestExpirePrefix(t *testing.T) {
+	d, err := New(KeepVersions(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, name := range []string{"cache/a", "cache/b/c", "cachex", "other"} {
+		d.Put(name, []byte(name), now, nil)
+	}
+	c, cancel := d.Subscribe(10)
+	defer cancel()
+	if n, err := d.ExpirePrefix("cache"); err != nil || n != 2 {
+		t.Errorf("ExpirePrefix = %d, %v; want 2", n, err)
+	}
+	if evs := drain(c); len(evs) != 2 || evs[0].Kind != EventRemoved {
+		t.Errorf("events = %+v, want two EventRemoved", evs)
+	}
+	if vs, _ := d.Versions("cache/a"); len(vs) != 1 {
+		t.Errorf("Versions = %+v, want the removed key kept", vs)
+	}
+	if n, err := d.ExpirePrefix(""); err != nil || n != 2 || d.Len() != 0 {
+		t.Errorf("ExpirePrefix(\"\") = %d, %v, leaving %d keys", n, err, d.Len())
+	}
+
+	d.Put("x", []byte("x"), now, nil)
+	d.Seal()
+	if _, err := d.ExpirePrefix(""); err == nil || !d.Exists("x") {
+		t.Errorf("ExpirePrefix of a sealed FS = %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package gomemfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// jsonEntry is the representation of a key used by ExportJSON and ImportJSON.
+type jsonEntry struct {
+	Name    string     `json:"name"`
+	Content []byte     `json:"content"`
+	Text    *string    `json:"text,omitempty"`
+	ModTime time.Time  `json:"modtime"`
+	Expire  *time.Time `json:"expire,omitempty"`
+	Mode    Mode       `json:"mode,omitempty"`
+	Link    string     `json:"link,omitempty"`
+
+	Redirect     string `json:"redirect,omitempty"`
+	RedirectCode int    `json:"redirect_code,omitempty"`
+}
+
+// ExportJSON writes every key currently held by the FS to w as an indented
+// JSON array of objects with "name", "content" (base64 encoded), "modtime"
+// and, if set, "expire" and "mode" members. Nothing is fulfilled. It is meant
+// for small filesystems such as test fixtures; use Save for anything large.
+func (d *FS) ExportJSON(w io.Writer) error {
+	ks := d.snapshot("")
+	es := make([]jsonEntry, len(ks))
+	for i, k := range ks {
+		b, err := k.content()
+		if err != nil {
+			return err
+		}
+		es[i] = jsonEntry{
+			Name:    k.name,
+			Content: b,
+			ModTime: k.modtime,
+			Expire:  k.expire,
+			Mode:    Mode(k.mode),
+			Link:    k.link,
+
+			Redirect:     k.redirect,
+			RedirectCode: k.redirectCode,
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(es); err != nil {
+		return fmt.Errorf("cannot export JSON: %w", err)
+	}
+	return nil
+}
+
+// ImportJSON reads a JSON array in the form written by ExportJSON from r and
+// puts every key in it into the FS, replacing keys of the same name. For
+// readability in hand-written fixtures, an entry may give its content as a
+// "text" string instead of base64 "content". A missing "modtime" is left as
+// the zero time. An entry with a "link" member is created with Symlink, and
+// one with "redirect" and "redirect_code" members with Redirect.
+func (d *FS) ImportJSON(r io.Reader) error {
+	var es []jsonEntry
+	if err := json.NewDecoder(r).Decode(&es); err != nil {
+		return fmt.Errorf("cannot import JSON: %w", err)
+	}
+	for _, e := range es {
+		if e.Link != "" {
+			if err := d.Symlink(e.Link, e.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if e.Redirect != "" {
+			if err := d.Redirect(e.Name, e.Redirect, e.RedirectCode); err != nil {
+				return err
+			}
+			continue
+		}
+		content := e.Content
+		if e.Text != nil {
+			content = []byte(*e.Text)
+		}
+		if content == nil {
+			content = []byte{}
+		}
+		if err := d.Put(e.Name, content, e.ModTime, e.Expire, e.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package gomemfs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportImportJSON(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Put("bin", []byte{0, 1, 2}, mt, nil, Mode(0o600))
+	d.Put("empty", nil, mt, nil)
+
+	var buf bytes.Buffer
+	if err := d.ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	i, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := i.ImportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := i.ReadFile("bin"); err != nil || !bytes.Equal(b, []byte{0, 1, 2}) {
+		t.Errorf("ReadFile = %v, %v", b, err)
+	}
+	if m, err := i.GetMeta("bin"); err != nil || m.Mode != 0o600 || !m.ModTime.Equal(mt) {
+		t.Errorf("GetMeta = %+v, %v", m, err)
+	}
+	if !i.Exists("empty") {
+		t.Error("empty key not imported")
+	}
+
+	fixture := `[{"name": "hello.txt", "text": "hello"}, {"name": "bad", "content": "!"}]`
+	if err := i.ImportJSON(strings.NewReader(fixture)); err == nil {
+		t.Error("ImportJSON accepted invalid base64")
+	}
+	fixture = `[{"name": "hello.txt", "text": "hello"}]`
+	if err := i.ImportJSON(strings.NewReader(fixture)); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := i.ReadFile("hello.txt"); err != nil || string(b) != "hello" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+}
@@ -2,6 +2,12 @@ package gomemfs
 
 import (
 	"bytes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"io/fs"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,8 +23,140 @@ type key struct {
 
 	// expire may be nil if the object never expires.
 	expire *time.Time
+
+	// hits counts how many times the key was served from the FS without
+	// running a Fulfiller.
+	hits uint64
+
+	// accessed is when the key was last served, and slots and slot count
+	// hits over the AccessWindow; see HotKeys and ColdKeys.
+	accessed time.Time
+	slots    [accessSlots]uint32
+	slot     int64
+
+	// delta is how long the key took to fulfill, for EarlyExpiry.
+	delta time.Duration
+
+	// fulfilled is set on keys stored by fulfillment rather than put, and
+	// used orders keys by last use, for EvictUnderPressure.
+	fulfilled bool
+	used      uint64
+
+	// stored is the time the key was put or fulfilled, and replaced the time
+	// it stopped being current, for keys kept by KeepVersions.
+	stored   time.Time
+	replaced time.Time
+
+	// mode holds the permission bits set with Mode.
+	mode fs.FileMode
+
+	// owner and pax are set with Owner and PAXRecords.
+	owner Owner
+	pax   map[string]string
+
+	// meta is set with Metadata.
+	meta map[string]string
+
+	// link is set if the key is a symbolic link created with Symlink, in
+	// which case it has no content.
+	link string
+
+	// redirect and redirectCode are set if the key was created with
+	// Redirect, in which case it has no content.
+	redirect     string
+	redirectCode int
+
+	// mapping is set if bytes is a file mapped by PutMapped, and keeps it
+	// mapped while the key is reachable.
+	mapping *mapping
+
+	// aead is set if bytes holds content encrypted with EncryptContent.
+	aead cipher.AEAD
+
+	// wipe is set if bytes is a private copy of the content, to be wiped
+	// once the FS no longer holds the key; see WipeContent.
+	wipe bool
+
+	// info is set on transient keys that were described by a StatSource
+	// instead of being fulfilled. They have no content and are never stored.
+	info fs.FileInfo
+
+	// stat is allocated on first use by fileStat.
+	statOnce sync.Once
+	stat     *FileStat
+
+	// sha is computed on first use by sum, since content never changes.
+	shaOnce sync.Once
+	sha     [sha256.Size]byte
+}
+
+func (k *key) open() (*File, error) {
+	b, err := k.content()
+	if err != nil {
+		return nil, err
+	}
+	f := &File{data: b, k: k}
+	f.r.Reset(b)
+	return f, nil
+}
+
+// fileStat returns the FileStat of k, which is only allocated once.
+func (k *key) fileStat() *FileStat {
+	k.statOnce.Do(func() {
+		k.stat = &FileStat{k: k}
+	})
+	return k.stat
+}
+
+func (k *key) sum() [sha256.Size]byte {
+	k.shaOnce.Do(func() {
+		b, _ := k.plain()
+		k.sha = sha256.Sum256(b)
+	})
+	return k.sha
+}
+
+func (k *key) clone(fs *FS) *key {
+	c := &key{
+		bytes:   k.bytes,
+		name:    k.name,
+		fs:      fs,
+		modtime: k.modtime,
+		expire:  k.expire,
+		mode:    k.mode,
+		stored:  k.stored,
+		owner:   k.owner,
+		pax:     k.pax,
+		meta:    k.meta,
+		link:    k.link,
+		aead:    k.aead,
+		mapping: k.mapping,
+
+		fulfilled:    k.fulfilled,
+		used:         k.used,
+		redirect:     k.redirect,
+		redirectCode: k.redirectCode,
+	}
+	if (k.wipe || fs.wipe) && k.mapping == nil {
+		// wiping either key must not affect the other
+		c.bytes, c.wipe = bytes.Clone(k.bytes), fs.wipe
+	}
+	return c
 }
 
-func (k *key) open() *File {
-	return &File{r: bytes.NewReader(k.bytes), k: k}
+// snapshot returns the keys currently held by the FS beneath the directory
+// prefix, which is empty or ends with a slash, sorted by name. Since content
+// is never modified in place, the keys can be read after the FS is unlocked.
+func (d *FS) snapshot(prefix string) []*key {
+	d.mu.Lock()
+	defer d.unlock()
+	ks := make([]*key, 0, d.keys.len())
+	prefix = d.fold(prefix)
+	for name := range d.keys.under(prefix) {
+		if k := d.lookup(name); k != nil {
+			ks = append(ks, k)
+		}
+	}
+	slices.SortFunc(ks, func(a, b *key) int { return strings.Compare(a.name, b.name) })
+	return ks
 }
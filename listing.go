@@ -0,0 +1,112 @@
+package gomemfs
+
+import (
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+// A Listing is the data a ListDirectories template is executed with.
+type Listing struct {
+	// Path is the directory being listed, with leading and trailing slashes,
+	// eg "/css/", relative to where the Handler is mounted.
+	Path    string
+	Entries []ListingEntry
+}
+
+// A ListingEntry describes a key or subdirectory in a Listing.
+type ListingEntry struct {
+	// Name is the name of the entry within the directory. Subdirectories
+	// have a trailing slash.
+	Name    string
+	Href    string // Name escaped for use as a relative link
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+// DefaultListing is the template used by ListDirectories if it is given nil.
+var DefaultListing = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Index of {{.Path}}</title>
+<style>body{font-family:sans-serif}td,th{padding:0 1em;text-align:left}td.n{text-align:right}</style>
+</head><body>
+<h1>Index of {{.Path}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Modified</th></tr>
+{{if ne .Path "/"}}<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Entries}}<tr>
+<td><a href="{{.Href}}">{{.Name}}</a></td>
+<td class="n">{{if not .IsDir}}{{.Size}}{{end}}</td>
+<td>{{if not .IsDir}}{{.ModTime.Format "2006-01-02 15:04:05 MST"}}{{end}}</td>
+</tr>
+{{end}}</table>
+</body></html>
+`))
+
+// ListDirectories causes a Handler to serve an HTML listing of the keys and
+// subdirectories in a directory without an index file, instead of a 404, by
+// executing t with a Listing. If t is nil, DefaultListing is used. As with
+// FS.ReadDir, keys that have not been fulfilled yet are not listed. Requests
+// for a directory without a trailing slash are redirected to add one, so that
+// relative links in the listing work.
+func ListDirectories(t *template.Template) HandlerOption {
+	if t == nil {
+		t = DefaultListing
+	}
+	return listDirectories{t}
+}
+
+type listDirectories struct {
+	t *template.Template
+}
+
+func (o listDirectories) applyToHandler(h *Handler) {
+	h.listing = o.t
+}
+
+// serveListing serves the listing of directory name, opened as f, if
+// listings are enabled.
+func (h *Handler) serveListing(w http.ResponseWriter, r *http.Request, name string, f fs.File) {
+	rd, ok := f.(fs.ReadDirFile)
+	if h.listing == nil || !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !strings.HasSuffix(r.URL.Path, "/") {
+		// relative, like http.FileServer, so it works beneath StripPrefix
+		loc := path.Base(r.URL.Path) + "/"
+		if r.URL.RawQuery != "" {
+			loc += "?" + r.URL.RawQuery
+		}
+		w.Header().Set("Location", loc)
+		w.WriteHeader(http.StatusMovedPermanently)
+		return
+	}
+	des, err := rd.ReadDir(-1)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	l := Listing{Path: "/", Entries: make([]ListingEntry, 0, len(des))}
+	if name != "." {
+		l.Path += name + "/"
+	}
+	for _, de := range des {
+		fi, err := de.Info()
+		if err != nil {
+			continue
+		}
+		e := ListingEntry{Name: de.Name(), Size: fi.Size(), ModTime: fi.ModTime(), IsDir: de.IsDir()}
+		if e.IsDir {
+			e.Name += "/"
+		}
+		e.Href = "./" + (&url.URL{Path: e.Name}).EscapedPath()
+		l.Entries = append(l.Entries, e)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	h.listing.Execute(w, l)
+}
@@ -0,0 +1,41 @@
+package gomemfs
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListDirectories(t *testing.T) {
+	d, err := New(IndexFiles("index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	d.Put("files/a b.txt", []byte("hello"), now, nil)
+	d.Put("files/sub/c.txt", []byte("c"), now, nil)
+	d.Put("site/index.html", []byte("<h1>home"), now, nil)
+
+	if w := get(FileServer(d), "/files/"); w.Code != http.StatusNotFound {
+		t.Errorf("GET without ListDirectories = %d, want 404", w.Code)
+	}
+	h := FileServer(d, ListDirectories(nil))
+	w := get(h, "/files/")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `<a href="./a%20b.txt">a b.txt</a>`) ||
+		!strings.Contains(w.Body.String(), `<a href="./sub/">sub/</a>`) {
+		t.Errorf("GET /files/ = %d %s", w.Code, w.Body)
+	}
+	if w := get(h, "/files?x=1"); w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "files/?x=1" {
+		t.Errorf("GET /files = %d to %q, want a redirect adding a slash", w.Code, w.Header().Get("Location"))
+	}
+	if w := get(h, "/site/"); w.Body.String() != "<h1>home" {
+		t.Errorf("GET of a directory with an index file = %q", w.Body)
+	}
+
+	tmpl := template.Must(template.New("").Parse(`{{.Path}}:{{range .Entries}} {{.Name}}{{if not .IsDir}}={{.Size}}{{end}}{{end}}`))
+	if w := get(FileServer(d, ListDirectories(tmpl)), "/files/"); w.Body.String() != "/files/: a b.txt=5 sub/" {
+		t.Errorf("listing = %q", w.Body)
+	}
+}
@@ -0,0 +1,81 @@
+package gomemfs
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogLevels sets the [slog.Level] used for each kind of message logged by an
+// FS configured WithLogger. It is also an FSOption, so the levels can be
+// changed independently of the logger.
+type LogLevels struct {
+	// Put is used when a key is stored with FS.Put.
+	Put slog.Level
+
+	// Fulfill is used when a Fulfiller produces content for a key.
+	Fulfill slog.Level
+
+	// FulfillError is used when a Fulfiller returns an error.
+	FulfillError slog.Level
+
+	// Expire is used when a key is discarded because it has expired.
+	Expire slog.Level
+
+	// Evict is used when the FS discards a key before it has expired.
+	Evict slog.Level
+
+	// Remove is used when a key is removed by a call to FS.Expire.
+	Remove slog.Level
+}
+
+// DefaultLogLevels are the levels a new FS uses unless LogLevels is set.
+var DefaultLogLevels = LogLevels{
+	Put:          slog.LevelDebug,
+	Fulfill:      slog.LevelDebug,
+	FulfillError: slog.LevelWarn,
+	Expire:       slog.LevelDebug,
+	Evict:        slog.LevelInfo,
+	Remove:       slog.LevelDebug,
+}
+
+func (fso LogLevels) applyTo(fs *FS) error {
+	fs.logLevels = fso
+	return nil
+}
+
+// WithLogger returns an FSOption that causes an FS to log its activity to l.
+// By default an FS is silent. Passing a nil l disables logging again.
+func WithLogger(l *slog.Logger) FSOption {
+	return loggerOption{l: l}
+}
+
+type loggerOption struct {
+	l *slog.Logger
+}
+
+func (fso loggerOption) applyTo(fs *FS) error {
+	fs.logger = fso.l
+	return nil
+}
+
+func (d *FS) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if d.logger == nil {
+		return
+	}
+	d.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func (d *FS) logKey(ctx context.Context, level slog.Level, msg string, k *key) {
+	if d.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("name", k.name),
+		slog.Int64("size", k.size()),
+		slog.Time("modtime", k.modtime),
+	}
+	if k.expire != nil {
+		attrs = append(attrs, slog.Time("expire", *k.expire))
+	}
+	d.log(ctx, level, msg, attrs...)
+}
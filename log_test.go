@@ -0,0 +1,47 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	d, err := New(WithLogger(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return nil, nil, nil, errors.New("boom")
+	})
+	d.Put("a", []byte("a"), time.Now(), nil)
+	d.ReadFile("b")
+	out := buf.String()
+	if strings.Contains(out, "gomemfs put") {
+		t.Errorf("put logged below the handler's level:\n%s", out)
+	}
+	if !strings.Contains(out, "gomemfs fulfiller failed") || !strings.Contains(out, "boom") {
+		t.Errorf("fulfiller error not logged:\n%s", out)
+	}
+
+	buf.Reset()
+	levels := DefaultLogLevels
+	levels.Put = slog.LevelInfo
+	d.Set(levels)
+	d.Put("a", []byte("a"), time.Now(), nil)
+	if !strings.Contains(buf.String(), "gomemfs put") || !strings.Contains(buf.String(), "name=a") {
+		t.Errorf("put not logged at LogLevels.Put:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	d.Set(WithLogger(nil))
+	d.ReadFile("b")
+	if buf.Len() != 0 {
+		t.Errorf("logged after WithLogger(nil):\n%s", buf.String())
+	}
+}
@@ -0,0 +1,51 @@
+package gomemfs
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// A ManifestEntry describes one key in an FS without its content.
+type ManifestEntry struct {
+	Name    string     `json:"name"`
+	Size    int64      `json:"size"`
+	ModTime time.Time  `json:"modtime"`
+	Expire  *time.Time `json:"expire,omitempty"`
+
+	// Link is the target of a symbolic link created with Symlink.
+	Link string `json:"link,omitempty"`
+
+	// SHA256 is the hex-encoded SHA-256 digest of the content.
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest returns a description of every key currently held by the FS,
+// sorted by name. Expired keys and keys that have not been fulfilled yet are
+// not included, and nothing is fulfilled.
+func (d *FS) Manifest() []ManifestEntry {
+	ks := d.snapshot("")
+
+	// content is immutable, so hashing can happen without holding the lock
+	m := make([]ManifestEntry, len(ks))
+	for i, k := range ks {
+		sum := k.sum()
+		m[i] = ManifestEntry{
+			Name:    k.name,
+			Size:    k.size(),
+			ModTime: k.modtime,
+			Expire:  k.expire,
+			Link:    k.link,
+			SHA256:  hex.EncodeToString(sum[:]),
+		}
+	}
+	return m
+}
+
+// WriteManifest writes the result of Manifest to w as an indented JSON array.
+func (d *FS) WriteManifest(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(d.Manifest())
+}
@@ -0,0 +1,44 @@
+package gomemfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestManifest(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	past := now.Add(-time.Minute)
+	d.Put("b", []byte("bee"), now, nil)
+	d.Put("a", []byte("ay"), now, nil)
+	d.Put("stale", []byte("x"), now, &past)
+	d.Symlink("a", "link")
+
+	m := d.Manifest()
+	if len(m) != 3 || m[0].Name != "a" || m[1].Name != "b" || m[2].Name != "link" {
+		t.Fatalf("Manifest = %+v", m)
+	}
+	sum := sha256.Sum256([]byte("bee"))
+	if m[1].Size != 3 || m[1].SHA256 != hex.EncodeToString(sum[:]) || !m[1].ModTime.Equal(now) {
+		t.Errorf("entry = %+v", m[1])
+	}
+	if m[2].Link != "a" {
+		t.Errorf("link entry = %+v", m[2])
+	}
+
+	var buf bytes.Buffer
+	if err := d.WriteManifest(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got []ManifestEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil || len(got) != 3 || got[1].SHA256 != m[1].SHA256 {
+		t.Errorf("WriteManifest = %s, %v", buf.Bytes(), err)
+	}
+}
@@ -0,0 +1,59 @@
+package gomemfs
+
+import (
+	"bytes"
+	"io/fs"
+	"testing/fstest"
+)
+
+// FromMapFS returns a new FS, created with options o, holding every file in m
+// with its content, mode and modtime. Directories in m are skipped, since
+// directories in an FS are derived from its keys. Files with [fs.ModeSymlink]
+// set are created with Symlink, using their content as the target. The content
+// buffers of m are shared with the FS and must not be modified afterwards.
+func FromMapFS(m fstest.MapFS, o ...FSOption) (*FS, error) {
+	d, err := New(o...)
+	if err != nil {
+		return nil, err
+	}
+	for name, f := range m {
+		if f == nil || f.Mode.IsDir() {
+			continue
+		}
+		if f.Mode&fs.ModeSymlink != 0 {
+			if err := d.Symlink(string(f.Data), name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		content := f.Data
+		if content == nil {
+			content = []byte{}
+		}
+		if err := d.Put(name, content, f.ModTime, nil, Mode(f.Mode)); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// ToMapFS returns an [fstest.MapFS] holding a copy of every key currently in
+// the FS with its modtime, so that the contents can be compared or checked
+// with the tools in [testing/fstest]. Nothing is fulfilled.
+func (d *FS) ToMapFS() fstest.MapFS {
+	ks := d.snapshot("")
+	m := make(fstest.MapFS, len(ks))
+	for _, k := range ks {
+		b, _ := k.content()
+		m[k.name] = &fstest.MapFile{
+			Data:    bytes.Clone(b),
+			Mode:    k.mode,
+			ModTime: k.modtime,
+		}
+		if k.link != "" {
+			m[k.name].Data = []byte(k.relTarget())
+			m[k.name].Mode = fs.ModeSymlink
+		}
+	}
+	return m
+}
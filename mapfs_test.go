@@ -0,0 +1,44 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMapFS(t *testing.T) {
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := fstest.MapFS{
+		"dir":           {Mode: fs.ModeDir},
+		"dir/a.txt":     {Data: []byte("a"), Mode: 0o600, ModTime: mt},
+		"dir/empty":     {},
+		"dir/link":      {Data: []byte("a.txt"), Mode: fs.ModeSymlink},
+		"top/index.htm": {Data: []byte("i"), ModTime: mt},
+	}
+	d, err := FromMapFS(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("dir/link"); err != nil || string(b) != "a" {
+		t.Errorf("ReadFile(dir/link) = %q, %v", b, err)
+	}
+	if fi, err := d.Stat("dir/a.txt"); err != nil || fi.Mode() != 0o600 || !fi.ModTime().Equal(mt) {
+		t.Errorf("Stat = %v, %v", fi, err)
+	}
+
+	out := d.ToMapFS()
+	if len(out) != 4 {
+		t.Errorf("ToMapFS = %v", out)
+	}
+	if f := out["dir/link"]; f == nil || f.Mode != fs.ModeSymlink || string(f.Data) != "a.txt" {
+		t.Errorf("link = %+v", f)
+	}
+	if f := out["dir/a.txt"]; f == nil || string(f.Data) != "a" || f.Mode != 0o600 || !f.ModTime.Equal(mt) {
+		t.Errorf("dir/a.txt = %+v", f)
+	}
+	out["dir/a.txt"].Data[0] = 'x'
+	if b, _ := d.ReadFile("dir/a.txt"); string(b) != "a" {
+		t.Error("ToMapFS shares content with the FS")
+	}
+}
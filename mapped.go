@@ -0,0 +1,64 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"runtime"
+	"time"
+)
+
+// A mapping is shared by every key whose content is a memory-mapped file.
+// The file is unmapped once the mapping, and so every such key and every
+// File opened on one, is unreachable.
+type mapping struct {
+	size int
+}
+
+// PutMapped is like Put, but the content of key name is the file at path,
+// memory-mapped instead of read into memory, so that serving a large file
+// does not take twice its size in memory. The modtime of the key is that of
+// the file. The file must not be modified or truncated while it is mapped,
+// as reading a truncated mapping crashes the process. It is unmapped once the
+// key is gone and no File opened on it is reachable. Where memory mapping is
+// not supported, PutMapped returns an error wrapping [errors.ErrUnsupported].
+func (d *FS) PutMapped(name, path string, expire *time.Time, o ...PutOption) error {
+	mapErr := func(err error) error {
+		return &fs.PathError{Op: "putmapped", Path: name, Err: fmt.Errorf("cannot map %q: %w", path, err)}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return mapErr(err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return mapErr(err)
+	}
+	if !fi.Mode().IsRegular() {
+		return mapErr(errors.New("not a regular file"))
+	}
+	if fi.Size() > math.MaxInt {
+		return mapErr(errors.ErrUnsupported)
+	}
+	k := &key{
+		bytes:   []byte{},
+		name:    name,
+		modtime: fi.ModTime(),
+		expire:  d.defaultExpire(expire),
+	}
+	if fi.Size() > 0 {
+		b, err := mmap(f, int(fi.Size()))
+		if err != nil {
+			return mapErr(err)
+		}
+		k.bytes, k.mapping = b, &mapping{size: len(b)}
+		runtime.AddCleanup(k.mapping, munmap, b)
+	}
+	for _, opt := range o {
+		opt.applyToKey(k)
+	}
+	return d.putKey(k)
+}
@@ -0,0 +1,66 @@
+package gomemfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPutMapped(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "big.bin")
+	if err := os.WriteFile(p, []byte("0123456789"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(p, mt, mt); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = d.PutMapped("big.bin", p, nil)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("big.bin"); err != nil || string(b) != "0123456789" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	r, err := d.OpenRange("big.bin", 3, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := io.ReadAll(r); err != nil || string(b) != "3456" {
+		t.Errorf("OpenRange = %q, %v", b, err)
+	}
+	if fi, err := d.Stat("big.bin"); err != nil || !fi.ModTime().Equal(mt) || fi.Size() != 10 {
+		t.Errorf("Stat = %v, %v", fi, err)
+	}
+
+	if err := d.PutMapped("empty", empty, nil); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("empty"); err != nil || len(b) != 0 {
+		t.Errorf("ReadFile(empty) = %q, %v", b, err)
+	}
+	var pe *fs.PathError
+	if err := d.PutMapped("dir", dir, nil); !errors.As(err, &pe) || pe.Op != "putmapped" || pe.Path != "dir" {
+		t.Errorf("PutMapped(dir) = %v, want a *fs.PathError", err)
+	}
+	if err := d.PutMapped("missing", filepath.Join(dir, "missing"), nil); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("PutMapped(missing) = %v, want os.ErrNotExist", err)
+	}
+}
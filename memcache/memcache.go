@@ -0,0 +1,189 @@
+// Package memcache provides a gomemfs RemoteStore backed by a memcached
+// server, speaking its text protocol with only the standard library.
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRelative is the longest expiry memcached accepts as a number of seconds;
+// longer ones must be given as a Unix time.
+const maxRelative = 30 * 24 * time.Hour
+
+// A Store is a RemoteStore for the memcached server at an address. Its methods
+// may be called concurrently; it keeps up to MaxIdle connections open between
+// calls.
+type Store struct {
+	addr string
+	idle chan *conn
+
+	// Dialer opens connections. If nil, a zero net.Dialer is used.
+	Dialer *net.Dialer
+}
+
+// MaxIdle is the number of idle connections a Store keeps open.
+const MaxIdle = 4
+
+// New returns a Store for the memcached server at addr, eg "localhost:11211".
+// No connection is made until the Store is used.
+func New(addr string) *Store {
+	return &Store{addr: addr, idle: make(chan *conn, MaxIdle)}
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Get implements gomemfs.RemoteStore.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.do(ctx, func(c *conn) error {
+		if _, err := fmt.Fprintf(c, "get %s\r\n", itemKey(key)); err != nil {
+			return err
+		}
+		line, err := readLine(c.r)
+		if err != nil {
+			return err
+		}
+		if line == "END" {
+			return fs.ErrNotExist
+		}
+		// VALUE <key> <flags> <bytes>
+		f := strings.Fields(line)
+		if len(f) < 4 || f[0] != "VALUE" {
+			return fmt.Errorf("memcache: unexpected reply %q", line)
+		}
+		n, err := strconv.Atoi(f[3])
+		if err != nil {
+			return fmt.Errorf("memcache: unexpected reply %q", line)
+		}
+		value = make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, value); err != nil {
+			return err
+		}
+		value = value[:n]
+		if line, err = readLine(c.r); err != nil {
+			return err
+		} else if line != "END" {
+			return fmt.Errorf("memcache: unexpected reply %q", line)
+		}
+		return nil
+	})
+	return value, err
+}
+
+// Set implements gomemfs.RemoteStore.
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	exp := int64(ttl.Round(time.Second) / time.Second)
+	if ttl > 0 && exp == 0 {
+		exp = 1
+	} else if ttl > maxRelative {
+		exp = time.Now().Add(ttl).Unix()
+	}
+	return s.do(ctx, func(c *conn) error {
+		if _, err := fmt.Fprintf(c, "set %s 0 %d %d\r\n", itemKey(key), exp, len(value)); err != nil {
+			return err
+		}
+		if _, err := c.Write(append(value[:len(value):len(value)], '\r', '\n')); err != nil {
+			return err
+		}
+		return expect(c.r, "STORED")
+	})
+}
+
+// Delete implements gomemfs.RemoteStore.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, func(c *conn) error {
+		if _, err := fmt.Fprintf(c, "delete %s\r\n", itemKey(key)); err != nil {
+			return err
+		}
+		line, err := readLine(c.r)
+		if err != nil {
+			return err
+		}
+		if line != "DELETED" && line != "NOT_FOUND" {
+			return fmt.Errorf("memcache: unexpected reply %q", line)
+		}
+		return nil
+	})
+}
+
+// do calls f with a connection, which is returned to the idle pool if f
+// succeeds or reports a miss, and closed otherwise.
+func (s *Store) do(ctx context.Context, f func(*conn) error) error {
+	var c *conn
+	select {
+	case c = <-s.idle:
+	default:
+		d := s.Dialer
+		if d == nil {
+			d = &net.Dialer{}
+		}
+		nc, err := d.DialContext(ctx, "tcp", s.addr)
+		if err != nil {
+			return err
+		}
+		c = &conn{Conn: nc, r: bufio.NewReader(nc)}
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		c.SetDeadline(dl)
+	} else {
+		c.SetDeadline(time.Time{})
+	}
+	err := f(c)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		c.Close()
+		return err
+	}
+	select {
+	case s.idle <- c:
+	default:
+		c.Close()
+	}
+	return err
+}
+
+// itemKey returns key if memcached accepts it, or else a digest of it, as keys
+// are limited to 250 bytes without spaces or control characters.
+func itemKey(key string) string {
+	ok := len(key) > 0 && len(key) <= 250
+	for i := 0; ok && i < len(key); i++ {
+		ok = key[i] > ' ' && key[i] != 0x7f
+	}
+	if ok {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\r\n"), nil
+}
+
+func expect(r *bufio.Reader, want string) error {
+	line, err := readLine(r)
+	if err != nil {
+		return err
+	}
+	if line != want {
+		return fmt.Errorf("memcache: unexpected reply %q", line)
+	}
+	return nil
+}
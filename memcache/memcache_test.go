@@ -0,0 +1,135 @@
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// server is a memcached speaking just enough of the text protocol for a
+// Store, recording the commands it receives.
+type server struct {
+	mu    sync.Mutex
+	items map[string][]byte
+	cmds  []string
+}
+
+func newServer(t *testing.T) (*server, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := &server{items: make(map[string][]byte)}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s, l.Addr().String()
+}
+
+func (s *server) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return
+		}
+		f := strings.Fields(line)
+		s.mu.Lock()
+		s.cmds = append(s.cmds, line)
+		switch f[0] {
+		case "get":
+			if v, ok := s.items[f[1]]; ok {
+				fmt.Fprintf(c, "VALUE %s 0 %d\r\n%s\r\n", f[1], len(v), v)
+			}
+			io.WriteString(c, "END\r\n")
+		case "set":
+			n, _ := strconv.Atoi(f[4])
+			v := make([]byte, n+2)
+			io.ReadFull(r, v)
+			s.items[f[1]] = v[:n]
+			io.WriteString(c, "STORED\r\n")
+		case "delete":
+			if _, ok := s.items[f[1]]; ok {
+				delete(s.items, f[1])
+				io.WriteString(c, "DELETED\r\n")
+			} else {
+				io.WriteString(c, "NOT_FOUND\r\n")
+			}
+		default:
+			io.WriteString(c, "ERROR\r\n")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestStore(t *testing.T) {
+	srv, addr := newServer(t)
+	s := New(addr)
+	ctx := context.Background()
+	if _, err := s.Get(ctx, "a"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Get = %v, want fs.ErrNotExist", err)
+	}
+	value := []byte("line one\r\nline two")
+	if err := s.Set(ctx, "a", value, 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Get(ctx, "a"); err != nil || string(got) != string(value) {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Errorf("Delete of a missing item = %v", err)
+	}
+
+	long := strings.Repeat("x", 300)
+	s.Set(ctx, long, []byte("long"), 0)
+	s.Set(ctx, "with space", []byte("spaced"), 40*24*time.Hour)
+	if got, err := s.Get(ctx, long); err != nil || string(got) != "long" {
+		t.Errorf("Get of a long key = %q, %v", got, err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.cmds[1] != "set a 0 90 18" {
+		t.Errorf("command = %q", srv.cmds[1])
+	}
+	for _, cmd := range srv.cmds[5:7] {
+		if f := strings.Fields(cmd); !strings.HasPrefix(f[1], "sha256:") {
+			t.Errorf("command = %q, want the key digested", cmd)
+		}
+	}
+	if exp, _ := strconv.ParseInt(strings.Fields(srv.cmds[6])[3], 10, 64); exp < time.Now().Unix() {
+		t.Errorf("command = %q, want a long expiry as a Unix time", srv.cmds[6])
+	}
+}
+
+func TestStoreDialError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	if _, err := New(addr).Get(context.Background(), "a"); err == nil || errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Get = %v, want a dial error", err)
+	}
+}
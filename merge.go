@@ -0,0 +1,60 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Merge copies every key currently held by other into d. Keys that already
+// exist in d are kept unless overwrite is true. Content buffers are shared
+// rather than copied, as with Clone. Keys are renamed according to d's
+// options, such as CaseInsensitive, but not passed to its Normalizers, as
+// their names were already normalized by other. Each key is stored as if by
+// Put, subject to eg MaxKeys and Quota; if one cannot be, Merge stops and
+// returns the error, keeping the keys merged before it.
+func (d *FS) Merge(other *FS, overwrite bool) error {
+	if other == d {
+		return errors.New("cannot merge FS into itself")
+	}
+	ks := other.snapshot("")
+
+	names := make([]string, len(ks))
+	for i, k := range ks {
+		n, err := d.clean(k.name)
+		if err != nil {
+			return fmt.Errorf("cannot merge key %q: %w", k.name, err)
+		}
+		names[i] = n
+	}
+
+	d.mu.Lock()
+	defer d.unlock()
+	if err := d.checkSealed("merge keys"); err != nil {
+		return err
+	}
+	for i, k := range ks {
+		if !overwrite && d.lookup(names[i]) != nil {
+			continue
+		}
+		c := k.clone(d)
+		c.name = names[i]
+		if err := d.store(c); err != nil {
+			return fmt.Errorf("cannot merge key %q: %w", k.name, err)
+		}
+	}
+	return nil
+}
+
+// MergeFulfillers adds the Fulfillers of other to d, as if they had been
+// passed to FulfillWith in the same order. They are run before any
+// Fulfillers d already had.
+func (d *FS) MergeFulfillers(other *FS) error {
+	if other == d {
+		return errors.New("cannot merge FS into itself")
+	}
+	other.mu.Lock()
+	f := slices.Clone(other.callbacks)
+	other.unlock()
+	return d.FulfillFrom(f...)
+}
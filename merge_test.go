@@ -0,0 +1,99 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	src, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Put("a", []byte("new a"), time.Now(), nil)
+	src.Put("b", []byte("new b"), time.Now(), nil)
+	src.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return []byte("src"), nil, nil, nil
+	})
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		if p == "mine" {
+			return []byte("d"), nil, nil, nil
+		}
+		return nil, nil, nil, nil
+	})
+	d.Put("a", []byte("old a"), time.Now(), nil)
+	if err := d.Merge(src, false); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := d.ReadFile("a"); string(b) != "old a" {
+		t.Errorf("a = %q, want it kept", b)
+	}
+	if b, _ := d.ReadFile("b"); string(b) != "new b" {
+		t.Errorf("b = %q", b)
+	}
+	if err := d.Merge(src, true); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := d.ReadFile("a"); string(b) != "new a" {
+		t.Errorf("a = %q, want it overwritten", b)
+	}
+	if err := d.Merge(d, true); err == nil {
+		t.Error("Merge of an FS into itself succeeded")
+	}
+
+	if err := d.MergeFulfillers(src); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := d.ReadFile("x"); string(b) != "src" {
+		t.Errorf("x = %q, want it from the merged Fulfiller", b)
+	}
+	if b, _ := d.ReadFile("mine"); string(b) != "src" {
+		t.Errorf("mine = %q, want merged Fulfillers run first", b)
+	}
+}
+
+func TestMergeAppliesLimits(t *testing.T) {
+	src, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Put("a", []byte("a"), time.Now(), nil)
+	src.Put("b", []byte("b"), time.Now(), nil)
+	d, err := New(MaxKeys(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Merge(src, false); !errors.Is(err, ErrFull) {
+		t.Errorf("Merge = %v, want ErrFull", err)
+	}
+}
+
+func TestMergeEncrypts(t *testing.T) {
+	src, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Put("a", []byte("plaintext"), time.Now(), nil)
+	d, err := New(EncryptContent(make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Merge(src, false); err != nil {
+		t.Fatal(err)
+	}
+	d.mu.Lock()
+	k := d.lookup("a")
+	d.unlock()
+	if k == nil || k.aead == nil || bytes.Contains(k.bytes, []byte("plaintext")) {
+		t.Error("key stored unencrypted")
+	}
+	if b, err := d.ReadFile("a"); err != nil || string(b) != "plaintext" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+}
@@ -0,0 +1,83 @@
+package gomemfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"io/fs"
+	"maps"
+	"path"
+	"time"
+)
+
+// Meta describes a key without its content, as returned by GetMeta.
+type Meta struct {
+	Size    int64
+	ModTime time.Time
+	Expire  *time.Time // nil if the key never expires
+	Mode    fs.FileMode
+
+	// SHA256 is the SHA-256 digest of the content. It is computed once per
+	// key and then remembered.
+	SHA256 [sha256.Size]byte
+
+	// Metadata holds the entries set with the Metadata PutOption. It is a
+	// copy, which may be modified.
+	Metadata map[string]string
+}
+
+// GetMeta returns the Meta of key name, following symbolic links and using
+// IndexFiles as Open does, without copying its content and without
+// fulfilling it: if the key is not held, the error wraps [fs.ErrNotExist].
+// This is cheaper than Stat and ReadFile for callers that only need eg the
+// headers to serve a key with. Like Peek, it does not count as a use of the
+// key, and NotFoundKey is not consulted.
+func (d *FS) GetMeta(name string) (Meta, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return Meta{}, &fs.PathError{Op: "getmeta", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	k, err := d.metaKey(n)
+	d.unlock()
+	if err != nil {
+		return Meta{}, &fs.PathError{Op: "getmeta", Path: name, Err: err}
+	}
+
+	// content is immutable, so hashing can happen without holding the lock
+	m := Meta{
+		Size:     k.size(),
+		ModTime:  k.modtime,
+		Expire:   k.expire,
+		Mode:     k.mode,
+		SHA256:   k.sum(),
+		Metadata: maps.Clone(k.meta),
+	}
+	return m, nil
+}
+
+// metaKey returns the key held for name for GetMeta, trying IndexFiles if name
+// is a directory.
+func (d *FS) metaKey(name string) (*key, error) {
+	// must be called with fs.mu Locked
+	name, err := d.follow(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.authorized(context.Background(), name); err != nil {
+		return nil, err
+	}
+	if k := d.lookup(name); k != nil {
+		return k, nil
+	}
+	for _, idx := range d.indexFiles {
+		if n, err := d.clean(path.Join(name, idx)); err == nil {
+			if k := d.peek(n); k != nil {
+				return k, nil
+			}
+		}
+	}
+	if d.isDir(name) {
+		return nil, errIsDir
+	}
+	return nil, fs.ErrNotExist
+}
@@ -0,0 +1,71 @@
+package gomemfs
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestGetMeta(t *testing.T) {
+	d, err := New(IndexFiles("index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls++
+		return []byte(p), nil, nil, nil
+	})
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expire := mt.Add(time.Hour * 24 * 365 * 10)
+	headers := Metadata{"Content-Type": "text/html"}
+	d.Put("site/index.html", []byte("<h1>"), mt, &expire, Mode(0o640), headers, Metadata{"Etag": `"1"`})
+	headers["Content-Type"] = "changed"
+	d.Symlink("site", "home")
+
+	m, err := d.GetMeta("home")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Size != 4 || !m.ModTime.Equal(mt) || m.Expire == nil || !m.Expire.Equal(expire) || m.Mode != 0o640 ||
+		m.SHA256 != sha256.Sum256([]byte("<h1>")) {
+		t.Errorf("GetMeta = %+v", m)
+	}
+	if len(m.Metadata) != 2 || m.Metadata["Content-Type"] != "text/html" || m.Metadata["Etag"] != `"1"` {
+		t.Errorf("Metadata = %v, want both entries", m.Metadata)
+	}
+	m.Metadata["Etag"] = "changed"
+	if m, _ := d.GetMeta("site/index.html"); m.Metadata["Etag"] != `"1"` {
+		t.Error("Metadata returned by GetMeta is not a copy")
+	}
+	if m, _ := d.GetMeta("site/index.html"); m.Metadata["Content-Type"] != "text/html" {
+		t.Error("Metadata passed to Put is not copied")
+	}
+
+	if _, err := d.GetMeta("other"); !errors.Is(err, fs.ErrNotExist) || calls != 0 {
+		t.Errorf("GetMeta = %v after %d calls, want fs.ErrNotExist without fulfilling", err, calls)
+	}
+	d.Put("docs/a", nil, mt, nil)
+	if _, err := d.GetMeta("docs"); err == nil {
+		t.Error("GetMeta of a directory succeeded")
+	}
+}
+
+func TestGetMetaNotFoundKey(t *testing.T) {
+	d, err := New(NotFoundKey("404.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("404.html", []byte("not found"), time.Now(), nil)
+	if _, err := d.GetMeta("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("GetMeta of a missing key = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := d.GetMeta("404.html"); err != nil {
+		t.Fatal(err)
+	}
+	if hot := d.HotKeys(1); len(hot) != 0 {
+		t.Errorf("HotKeys = %v, want GetMeta not to count as a hit", hot)
+	}
+}
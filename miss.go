@@ -0,0 +1,49 @@
+package gomemfs
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// A MissError is returned, usually wrapped in an [fs.PathError], when no
+// Fulfiller produced content for a key. It unwraps to [fs.ErrNotExist]. Its
+// Attempts tell a key that no Fulfiller was ever consulted for apart from one
+// that Fulfillers declined.
+type MissError struct {
+	Name     string
+	Attempts []MissAttempt
+}
+
+// A MissAttempt records a Fulfiller (or the Fallback) consulted for a key
+// that it did not produce.
+type MissAttempt struct {
+	// Fulfiller is the index of the Fulfiller in the order passed to
+	// FulfillWith, or -1 for the Fallback.
+	Fulfiller     int
+	FulfillerName string
+
+	// Err is the error wrapping [fs.ErrNotExist] returned by the Fulfiller,
+	// ErrCircuitOpen if it was skipped, or nil if it returned nil content.
+	Err error
+}
+
+func (e *MissError) Error() string {
+	if len(e.Attempts) == 0 {
+		return fmt.Sprintf("no fulfiller for %q: %v", e.Name, fs.ErrNotExist)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d fulfillers declined %q:", len(e.Attempts), e.Name)
+	for _, a := range e.Attempts {
+		fmt.Fprintf(&b, " [%d %s", a.Fulfiller, a.FulfillerName)
+		if a.Err != nil {
+			fmt.Fprintf(&b, ": %v", a.Err)
+		}
+		b.WriteString("]")
+	}
+	return b.String()
+}
+
+func (e *MissError) Unwrap() error {
+	return fs.ErrNotExist
+}
@@ -0,0 +1,44 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMissError(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = d.ReadFile("a")
+	var me *MissError
+	if !errors.As(err, &me) || len(me.Attempts) != 0 || !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile with no Fulfillers = %v", err)
+	}
+
+	errGone := fmt.Errorf("gone: %w", fs.ErrNotExist)
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return nil, nil, nil, nil
+	})
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return nil, nil, nil, errGone
+	})
+	d.Set(Fallback(fstest.MapFS{}))
+	_, err = d.ReadFile("a")
+	if !errors.As(err, &me) || me.Name != "a" || len(me.Attempts) != 3 {
+		t.Fatalf("ReadFile = %v", err)
+	}
+	if a := me.Attempts[0]; a.Fulfiller != 1 || a.Err != errGone || a.FulfillerName == "" {
+		t.Errorf("first attempt = %+v", a)
+	}
+	if a := me.Attempts[1]; a.Fulfiller != 0 || a.Err != nil {
+		t.Errorf("second attempt = %+v", a)
+	}
+	if a := me.Attempts[2]; a.Fulfiller != -1 {
+		t.Errorf("third attempt = %+v, want the Fallback", a)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package gomemfs
+
+import (
+	"errors"
+	"os"
+)
+
+func mmap(f *os.File, size int) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func munmap(b []byte) {}
@@ -0,0 +1,75 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"time"
+)
+
+// A Namespace is a view of the keys of an FS beneath one directory, returned
+// by FS.Namespace, for keeping the keys of eg many tenants apart in one FS.
+// Names passed to a Namespace are relative to its directory and cannot refer
+// to anything outside it, as for a SubFS, whose methods it has. Fulfillers of
+// the FS receive names with the directory of the Namespace prepended.
+type Namespace struct {
+	*SubFS
+}
+
+// Namespace returns a Namespace of the keys beneath the directory id, which
+// must be valid according to [fs.ValidPath] and may contain slashes, as in
+// "tenants/42".
+func (d *FS) Namespace(id string) (*Namespace, error) {
+	if !fs.ValidPath(id) || id == "." {
+		return nil, &fs.PathError{Op: "namespace", Path: id, Err: fs.ErrInvalid}
+	}
+	sub, err := d.sub(id)
+	if err != nil {
+		return nil, err
+	}
+	return &Namespace{sub}, nil
+}
+
+// Put is like FS.Put.
+func (ns *Namespace) Put(name string, content []byte, modtime time.Time, expire *time.Time, o ...PutOption) error {
+	n, err := ns.join("put", name)
+	if err != nil {
+		return err
+	}
+	return ns.fixErr(ns.p.Put(n, content, modtime, expire, o...))
+}
+
+// Expire is like FS.Expire.
+func (ns *Namespace) Expire(name string) error {
+	n, err := ns.join("expire", name)
+	if err != nil {
+		return err
+	}
+	return ns.fixErr(ns.p.Expire(n))
+}
+
+// Len reports the number of keys currently stored in the Namespace.
+func (ns *Namespace) Len() int {
+	keys, _ := ns.Usage()
+	return keys
+}
+
+// SetQuota sets a Quota on the Namespace, limiting it to maxBytes of
+// content and maxKeys keys, where either limit is ignored if it is not
+// greater than zero.
+func (ns *Namespace) SetQuota(maxBytes int64, maxKeys int) error {
+	return ns.p.Set(Quota{Prefix: ns.d, MaxBytes: maxBytes, MaxKeys: maxKeys})
+}
+
+// Usage returns the number of keys and bytes of content held by the
+// Namespace.
+func (ns *Namespace) Usage() (keys int, bytes int64) {
+	prefix := ns.p.fold(ns.root) + "/"
+	ns.p.mu.Lock()
+	defer ns.p.unlock()
+	for name := range ns.p.keys.under(prefix) {
+		if k := ns.p.lookup(name); k != nil {
+			keys++
+			bytes += k.size()
+		}
+	}
+	return keys, bytes
+}
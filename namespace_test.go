@@ -0,0 +1,60 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestNamespace(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var asked []string
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		asked = append(asked, p)
+		return []byte(p), nil, nil, nil
+	})
+	if _, err := d.Namespace("../x"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Namespace(../x) = %v, want fs.ErrInvalid", err)
+	}
+	a, err := d.Namespace("tenants/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := d.Namespace("tenants/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	a.Put("conf", []byte("a"), now, nil)
+	b.Put("conf", []byte("bb"), now, nil)
+	if got, err := fs.ReadFile(a, "conf"); err != nil || string(got) != "a" {
+		t.Errorf("ReadFile = %q, %v", got, err)
+	}
+	if !d.Exists("tenants/b/conf") {
+		t.Error("Namespace key not stored beneath its directory")
+	}
+	if _, err := fs.ReadFile(a, "../b/conf"); err == nil {
+		t.Error("Namespace read outside its directory")
+	}
+	if got, err := fs.ReadFile(a, "page"); err != nil || string(got) != "tenants/a/page" || asked[0] != "tenants/a/page" {
+		t.Errorf("fulfilled %q, %v for names %q", got, err, asked)
+	}
+	if keys, bytes := b.Usage(); keys != 1 || bytes != 2 || b.Len() != 1 {
+		t.Errorf("Usage = %d, %d", keys, bytes)
+	}
+
+	if err := b.SetQuota(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	var qe *QuotaError
+	if err := b.Put("more", []byte("x"), now, nil); !errors.As(err, &qe) {
+		t.Errorf("Put over quota = %v", err)
+	}
+	if err := b.Expire("conf"); err != nil || b.Len() != 0 {
+		t.Errorf("Expire = %v, leaving %d keys", err, b.Len())
+	}
+}
@@ -0,0 +1,55 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"slices"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// A Normalizer rewrites every name passed to an FS before it is cleaned and
+// checked, so that names which should refer to the same key do. An error
+// rejects the name. Normalizer is an FSOption; each one given is run in turn,
+// in the order they were given, and they can only be set if the FS is empty.
+type Normalizer func(name string) (string, error)
+
+func (fso Normalizer) applyTo(fs *FS) error {
+	if fs.keys.len() > 0 {
+		return errors.New("cannot add a normalizer with existing keys")
+	}
+	// clipped, since a clone shares the slice
+	fs.normalizers = append(slices.Clip(fs.normalizers), fso)
+	return nil
+}
+
+// PercentDecode is a Normalizer that decodes percent-encoded names as found
+// in URLs, so that eg "a%20b.txt" is the same key as "a b.txt". Names that are
+// not validly encoded are rejected with [fs.ErrInvalid].
+var PercentDecode = Normalizer(func(name string) (string, error) {
+	n, err := url.PathUnescape(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", fs.ErrInvalid, err)
+	}
+	return n, nil
+})
+
+// NFC is a Normalizer that converts names to Unicode Normalization Form C, so
+// that names composed differently on different operating systems, such as
+// "é" written as one code point or as "e" and a combining accent, are the
+// same key.
+var NFC = NormalizeWith(norm.NFC.String)
+
+// NFKC is like NFC, but uses Normalization Form KC, which also folds
+// compatibility characters such as ligatures and full-width forms, so that eg
+// "ﬁle" is the same key as "file".
+var NFKC = NormalizeWith(norm.NFKC.String)
+
+// NormalizeWith returns a Normalizer that applies f to every name.
+func NormalizeWith(f func(string) string) Normalizer {
+	return func(name string) (string, error) {
+		return f(name), nil
+	}
+}
@@ -0,0 +1,131 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPercentDecodeOnce(t *testing.T) {
+	d, err := New(PercentDecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := d.Create("a%2541")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Name() != "a%41" {
+		t.Errorf("Name = %q, want %q", w.Name(), "a%41")
+	}
+	w.Write([]byte("x"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := d.ReadFile("a%2541"); err != nil || string(b) != "x" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if d.Exists("aA") {
+		t.Error("key was decoded twice")
+	}
+
+	if err := d.Symlink("b%2541", "link"); err != nil {
+		t.Fatal(err)
+	}
+	d.Put("b%2541", []byte("y"), time.Now(), nil)
+	if b, err := d.ReadFile("link"); err != nil || string(b) != "y" {
+		t.Errorf("ReadFile through link = %q, %v", b, err)
+	}
+}
+
+func TestMergeDoesNotRenormalize(t *testing.T) {
+	src, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.Put("x%2541", []byte("x"), time.Now(), nil)
+	d, err := New(PercentDecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Merge(src, false); err != nil {
+		t.Fatal(err)
+	}
+	if d.Exists("xA") || !d.Exists("x%252541") {
+		t.Error("merged key was renormalized")
+	}
+
+	r, err := New(PercentDecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.ReplaceAll(src); err != nil {
+		t.Fatal(err)
+	}
+	if r.Exists("xA") {
+		t.Error("replaced key was renormalized")
+	}
+}
+
+func TestNFC(t *testing.T) {
+	d, err := New(NFC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("café.txt", []byte("x"), time.Now(), nil)
+	if b, err := d.ReadFile("café.txt"); err != nil || !bytes.Equal(b, []byte("x")) {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+
+	k, err := New(NFKC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.Put("ﬁle", []byte("x"), time.Now(), nil)
+	if !k.Exists("file") {
+		t.Error("NFKC did not fold the ligature")
+	}
+}
+
+func TestNormalizers(t *testing.T) {
+	d, err := New(PercentDecode, NormalizeWith(strings.ToLower))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("Docs/A%20B.txt", []byte("x"), time.Now(), nil)
+	if !d.Exists("docs/a b.txt") {
+		t.Error("Normalizers not run in order")
+	}
+	if _, err := d.Open("bad%zz"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Open of an invalid encoding = %v, want fs.ErrInvalid", err)
+	}
+	if err := d.Set(NFC); err == nil {
+		t.Error("Normalizer added to an FS with keys")
+	}
+}
+
+func TestPercentDecodeReadDirHandle(t *testing.T) {
+	d, err := New(PercentDecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a%2525/x", []byte("x"), time.Now(), nil)
+	f, err := d.Open("a%2525")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	es, err := f.(fs.ReadDirFile).ReadDir(-1)
+	if err != nil || len(es) != 1 || es[0].Name() != "x" {
+		t.Errorf("ReadDir = %v, %v", es, err)
+	}
+
+	w := get(FileServer(d, ListDirectories(nil)), "/a%252525/")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), ">x</a>") {
+		t.Errorf("listing = %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,68 @@
+package gomemfs
+
+import "strings"
+
+// NotFoundKey returns an FSOption that causes any missing key under one of
+// prefixes to be served with the content of key instead, as is needed to serve
+// the client-side routes of a single-page application. If no prefixes are
+// given, the rule applies to every missing key. When several rules match, the
+// one with the longest prefix is used. Calling NotFoundKey again for a prefix
+// replaces its rule, and an empty key removes it.
+func NotFoundKey(key string, prefixes ...string) FSOption {
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	return notFoundOption{key: key, prefixes: prefixes}
+}
+
+type notFoundOption struct {
+	key      string
+	prefixes []string
+}
+
+func (fso notFoundOption) applyTo(fs *FS) error {
+	nf := make(map[string]string, len(fs.notFound)+len(fso.prefixes))
+	for p, k := range fs.notFound {
+		nf[p] = k
+	}
+	for _, p := range fso.prefixes {
+		p = strings.Trim(p, "/")
+		if fso.key == "" {
+			delete(nf, p)
+		} else {
+			nf[p] = fso.key
+		}
+	}
+	fs.notFound = nf
+	return nil
+}
+
+func (d *FS) notFoundKey(root, name string) string {
+	// must be called with fs.mu Locked
+	// a key outside root is not used, as a SubFS may not serve it
+	var best, key string
+	var found bool
+	folded := d.fold(name)
+	for p, k := range d.notFound {
+		if found && len(p) <= len(best) {
+			continue
+		}
+		if p != "" {
+			if d.caseInsensitive || d.casePreserving {
+				p = strings.ToLower(p)
+			}
+			if !strings.HasPrefix(folded, p) || (len(folded) > len(p) && folded[len(p)] != '/') {
+				continue
+			}
+		}
+		best, key, found = p, k, true
+	}
+	if !found {
+		return ""
+	}
+	n, err := d.normalize(key)
+	if err != nil || d.fold(n) == folded || !d.within(root, n) {
+		return ""
+	}
+	return n
+}
@@ -0,0 +1,36 @@
+package gomemfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNotFoundKey(t *testing.T) {
+	d, err := New(NotFoundKey("app/index.html", "app"), NotFoundKey("404.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("app/index.html", []byte("spa"), time.Now(), nil)
+	d.Put("app/main.js", []byte("js"), time.Now(), nil)
+	d.Put("404.html", []byte("not found"), time.Now(), nil)
+
+	for name, want := range map[string]string{
+		"app/main.js":       "js",
+		"app/users/42":      "spa",
+		"apple":             "not found",
+		"docs/missing.html": "not found",
+	} {
+		if b, err := d.ReadFile(name); err != nil || string(b) != want {
+			t.Errorf("ReadFile(%q) = %q, %v; want %q", name, b, err, want)
+		}
+	}
+
+	d.Set(NotFoundKey("", "app"))
+	if b, _ := d.ReadFile("app/users/42"); string(b) != "not found" {
+		t.Errorf("ReadFile = %q after removing the app rule", b)
+	}
+	d.Expire("404.html")
+	if _, err := d.ReadFile("docs/missing.html"); err == nil {
+		t.Error("missing NotFoundKey served")
+	}
+}
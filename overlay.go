@@ -0,0 +1,143 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// An OverlayFS layers several [fs.FS] implementations on top of each other.
+// A name is resolved by trying each layer in order until one of them has it,
+// so earlier layers shadow later ones. Directories that exist in more than one
+// layer are merged when read. This allows eg a handful of files in an FS to
+// override a large embed.FS without copying it into memory.
+type OverlayFS struct {
+	layers []fs.FS
+}
+
+// Overlay returns an OverlayFS of layers, highest precedence first.
+func Overlay(layers ...fs.FS) *OverlayFS {
+	return &OverlayFS{layers: slices.Clone(layers)}
+}
+
+// Open implements [fs.FS].
+func (o *OverlayFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, l := range o.layers {
+		f, err := l.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fi, err := f.Stat(); err == nil && fi.IsDir() {
+			return &overlayDir{File: f, o: o, name: name}, nil
+		}
+		return f, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadFile implements [fs.ReadFileFS].
+func (o *OverlayFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, l := range o.layers {
+		b, err := fs.ReadFile(l, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return b, err
+	}
+	return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat implements [fs.StatFS].
+func (o *OverlayFS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	for _, l := range o.layers {
+		fi, err := fs.Stat(l, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return fi, err
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir implements [fs.ReadDirFS]. The entries of every layer that has a
+// directory called name are merged, with entries in earlier layers shadowing
+// entries of the same name in later ones. Merging stops at the first layer
+// where name exists but cannot be read as a directory.
+func (o *OverlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	var found bool
+	var entries []fs.DirEntry
+	seen := make(map[string]bool)
+	for _, l := range o.layers {
+		es, err := fs.ReadDir(l, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			if !found {
+				return nil, err
+			}
+			break
+		}
+		found = true
+		for _, e := range es {
+			if !seen[e.Name()] {
+				seen[e.Name()] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// Sub implements [fs.SubFS].
+func (o *OverlayFS) Sub(dir string) (fs.FS, error) {
+	if !fs.ValidPath(dir) {
+		return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+	}
+	if dir == "." {
+		return o, nil
+	}
+	layers := make([]fs.FS, len(o.layers))
+	for i, l := range o.layers {
+		s, err := fs.Sub(l, dir)
+		if err != nil {
+			return nil, err
+		}
+		layers[i] = s
+	}
+	return &OverlayFS{layers: layers}, nil
+}
+
+// overlayDir is a directory opened from an OverlayFS, whose entries are merged
+// across all layers.
+type overlayDir struct {
+	fs.File
+	o    *OverlayFS
+	name string
+	list dirList
+}
+
+// ReadDir implements [fs.ReadDirFile].
+func (d *overlayDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	return d.list.next(func() ([]fs.DirEntry, error) { return d.o.ReadDir(d.name) }, n)
+}
@@ -0,0 +1,40 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestOverlay(t *testing.T) {
+	top := fstest.MapFS{
+		"index.html":   {Data: []byte("override")},
+		"static/extra": {Data: []byte("extra")},
+	}
+	base := fstest.MapFS{
+		"index.html":  {Data: []byte("base")},
+		"static/app":  {Data: []byte("app")},
+		"static/deep": {Data: []byte("deep")},
+	}
+	o := Overlay(top, base)
+	if b, err := fs.ReadFile(o, "index.html"); err != nil || string(b) != "override" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if b, err := fs.ReadFile(o, "static/app"); err != nil || string(b) != "app" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	es, err := fs.ReadDir(o, "static")
+	if err != nil || len(es) != 3 {
+		t.Errorf("ReadDir = %v, %v; want the layers merged", es, err)
+	}
+	if err := fstest.TestFS(o, "index.html", "static/app", "static/deep", "static/extra"); err != nil {
+		t.Error(err)
+	}
+	sub, err := fs.Sub(o, "static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fstest.TestFS(sub, "app", "deep", "extra"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,44 @@
+package gomemfs
+
+import "io/fs"
+
+// Exists reports whether name is a key currently held by the FS, following
+// symbolic links. Fulfillers are never run, and the key's hit count is not
+// changed, so Exists can be used to decide eg whether a key is warm without
+// side effects. Directories do not exist as keys.
+func (d *FS) Exists(name string) bool {
+	n, err := d.normalize(name)
+	if err != nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	return d.peek(n) != nil
+}
+
+// Peek is like Stat, but describes name only if it is a key currently held
+// by the FS, as for Exists; otherwise the error wraps [fs.ErrNotExist]. Unlike
+// Stat, it never fulfills, even with StatFulfills set.
+func (d *FS) Peek(name string) (fs.FileInfo, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "peek", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	k := d.peek(n)
+	if k == nil {
+		return nil, &fs.PathError{Op: "peek", Path: name, Err: fs.ErrNotExist}
+	}
+	return k.fileStat(), nil
+}
+
+// peek returns the key held for name, after following links, or nil.
+func (d *FS) peek(name string) *key {
+	// must be called with fs.mu Locked
+	n, err := d.follow(name)
+	if err != nil {
+		return nil
+	}
+	return d.lookup(n)
+}
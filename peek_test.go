@@ -0,0 +1,48 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestExistsAndPeek(t *testing.T) {
+	d, err := New(StatFulfills(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls++
+		return []byte(p), nil, nil, nil
+	})
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Put("docs/a.txt", []byte("hello"), mt, nil)
+	d.Symlink("docs/a.txt", "latest")
+
+	for name, want := range map[string]bool{
+		"docs/a.txt": true,
+		"latest":     true,
+		"docs":       false,
+		"missing":    false,
+		"../bad":     false,
+	} {
+		if got := d.Exists(name); got != want {
+			t.Errorf("Exists(%q) = %v, want %v", name, got, want)
+		}
+	}
+	fi, err := d.Peek("latest")
+	if err != nil || fi.Name() != "a.txt" || fi.Size() != 5 || !fi.ModTime().Equal(mt) {
+		t.Errorf("Peek = %v, %v", fi, err)
+	}
+	if _, err := d.Peek("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Peek = %v, want fs.ErrNotExist", err)
+	}
+	if calls != 0 {
+		t.Errorf("fulfilled %d times, want none", calls)
+	}
+	if hot := d.HotKeys(1); len(hot) != 0 {
+		t.Errorf("HotKeys = %+v, want no hits counted", hot)
+	}
+}
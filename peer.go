@@ -0,0 +1,196 @@
+package gomemfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The peer protocol lets one process mount the FS of another. A GET request
+// for a key's name beneath the base URL returns its content, with its modtime
+// and expiry in the headers below; a missing key is a 404. A GET request for
+// the base URL itself returns the Manifest of the FS as JSON.
+const (
+	peerModTime = "Gomemfs-Modtime"
+	peerExpire  = "Gomemfs-Expire" // absent if the key never expires
+)
+
+// PeerHandler returns an [http.Handler] serving the keys of d to PeerSources
+// in other processes, fulfilling them as needed. Keys are named by the request
+// path without its leading slash; use [http.StripPrefix] to mount the handler
+// below a path prefix. Like DebugHandler, it is meant for trusted peers only.
+func PeerHandler(d *FS) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		if name == "" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(d.Manifest())
+			return
+		}
+
+		f, err := d.OpenContext(r.Context(), name)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		defer f.Close()
+		mf, ok := f.(*File)
+		if !ok || mf.dir != "" {
+			// directories are not keys, so the peer should not store them
+			http.NotFound(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Content-Type", "application/octet-stream")
+		h.Set("Content-Length", strconv.Itoa(len(mf.data)))
+		h.Set(peerModTime, mf.k.modtime.Format(time.RFC3339Nano))
+		if mf.k.expire != nil {
+			h.Set(peerExpire, mf.k.expire.Format(time.RFC3339Nano))
+		}
+		w.Write(mf.data)
+	})
+}
+
+// A PeerSource fulfills paths from the FS of another process, served by
+// PeerHandler. It is created with Peer.
+type PeerSource struct {
+	base *url.URL
+	c    *http.Client
+}
+
+// Peer returns a PeerSource that fetches keys from the PeerHandler at base,
+// using c, or [http.DefaultClient] if c is nil. Keys keep the modtime and
+// expiry they have in the serving FS, so a chain of FSes stays consistent
+// with the one that fulfilled a key first.
+func Peer(base *url.URL, c *http.Client) *PeerSource {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	return &PeerSource{base: base, c: c}
+}
+
+// Fulfill implements Source.
+func (p *PeerSource) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return p.FulfillContext(context.Background(), name)
+}
+
+// FulfillContext implements ContextSource.
+func (p *PeerSource) FulfillContext(ctx context.Context, name string) ([]byte, *time.Time, *time.Time, error) {
+	resp, err := p.get(ctx, http.MethodGet, name)
+	if err != nil || resp == nil {
+		return nil, nil, nil, err
+	}
+	defer resp.Body.Close()
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot read %q from peer: %w", name, err)
+	}
+	mt, expire, err := peerTimes(resp.Header)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cannot read %q from peer: %w", name, err)
+	}
+	return buf, &mt, expire, nil
+}
+
+// Stat implements StatSource. The peer fulfills the key if needed.
+func (p *PeerSource) Stat(name string) (fs.FileInfo, error) {
+	resp, err := p.get(context.Background(), http.MethodHead, name)
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	mt, _, err := peerTimes(resp.Header)
+	if err != nil {
+		return nil, fmt.Errorf("cannot stat %q on peer: %w", name, err)
+	}
+	return &peerInfo{name: path.Base(name), size: resp.ContentLength, modtime: mt}, nil
+}
+
+// Manifest returns the Manifest of the peer's FS.
+func (p *PeerSource) Manifest(ctx context.Context) ([]ManifestEntry, error) {
+	resp, err := p.get(ctx, http.MethodGet, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("cannot read manifest from peer: %w", fs.ErrNotExist)
+	}
+	defer resp.Body.Close()
+	var m []ManifestEntry
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("cannot read manifest from peer: %w", err)
+	}
+	return m, nil
+}
+
+// get sends a request for name, returning a nil response if the peer does not
+// have it.
+func (p *PeerSource) get(ctx context.Context, method, name string) (*http.Response, error) {
+	u := p.base.JoinPath(name)
+	if name == "" && !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, nil
+	}
+	resp.Body.Close()
+	return nil, fmt.Errorf("cannot fetch %s from peer: %s", u.Redacted(), resp.Status)
+}
+
+// peerTimes returns the modtime and expiry sent by a PeerHandler.
+func peerTimes(h http.Header) (time.Time, *time.Time, error) {
+	mt, err := time.Parse(time.RFC3339Nano, h.Get(peerModTime))
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	v := h.Get(peerExpire)
+	if v == "" {
+		forever := Forever
+		return mt, &forever, nil
+	}
+	expire, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	return mt, &expire, nil
+}
+
+// peerInfo describes a key on a peer for Stat.
+type peerInfo struct {
+	name    string
+	size    int64
+	modtime time.Time
+}
+
+func (fi *peerInfo) Name() string       { return fi.name }
+func (fi *peerInfo) Size() int64        { return fi.size }
+func (fi *peerInfo) Mode() fs.FileMode  { return 0o444 }
+func (fi *peerInfo) ModTime() time.Time { return fi.modtime }
+func (fi *peerInfo) IsDir() bool        { return false }
+func (fi *peerInfo) Sys() any           { return nil }
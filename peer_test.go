@@ -0,0 +1,62 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestPeer(t *testing.T) {
+	origin, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expire := time.Now().Add(time.Hour).Truncate(time.Second)
+	origin.Put("css/app.css", []byte("body{}"), mt, &expire)
+	origin.Put("logo.png", []byte("png"), mt, nil)
+	srv := httptest.NewServer(http.StripPrefix("/peer", PeerHandler(origin)))
+	defer srv.Close()
+
+	base, _ := url.Parse(srv.URL + "/peer")
+	p := Peer(base, srv.Client())
+	d, err := New(StatFulfills(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(p)
+	if b, err := d.ReadFile("css/app.css"); err != nil || string(b) != "body{}" {
+		t.Fatalf("ReadFile = %q, %v", b, err)
+	}
+	if m, err := d.GetMeta("css/app.css"); err != nil || !m.ModTime.Equal(mt) || m.Expire == nil || !m.Expire.Equal(expire) {
+		t.Errorf("GetMeta = %+v, %v; want the times of the origin", m, err)
+	}
+	if fi, err := d.Stat("logo.png"); err != nil || fi.Size() != 3 || !fi.ModTime().Equal(mt) {
+		t.Errorf("Stat = %v, %v", fi, err)
+	}
+	d.ReadFile("logo.png")
+	if m, err := d.GetMeta("logo.png"); err != nil || m.Expire != nil {
+		t.Errorf("GetMeta = %+v, %v; want cached without expiring", m, err)
+	}
+	if _, err := d.ReadFile("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile = %v, want fs.ErrNotExist", err)
+	}
+	if w := get(PeerHandler(origin), "/css"); w.Code != http.StatusNotFound {
+		t.Errorf("GET of a directory = %d, want 404", w.Code)
+	}
+
+	m, err := p.Manifest(t.Context())
+	if err != nil || len(m) != 2 || m[0].Name != "css/app.css" || m[1].Name != "logo.png" {
+		t.Errorf("Manifest = %+v, %v", m, err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/logo.png", nil)
+	w := httptest.NewRecorder()
+	PeerHandler(origin).ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST = %d, want 405", w.Code)
+	}
+}
@@ -0,0 +1,73 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"maps"
+)
+
+// A PutOption sets metadata on a key stored with Put.
+type PutOption interface {
+	applyToKey(*key)
+}
+
+// Mode sets the permission bits of a key, as reported by FileStat.Mode and
+// used when the key is exported eg by WriteTar or WriteDir. Only the bits in
+// [fs.ModePerm], [fs.ModeSetuid], [fs.ModeSetgid] and [fs.ModeSticky] are
+// kept. Keys without a Mode report 0 and are exported as 0644.
+type Mode fs.FileMode
+
+func (o Mode) applyToKey(k *key) {
+	k.mode = fs.FileMode(o) & keyModeMask
+}
+
+const keyModeMask = fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky
+
+// exportMode returns the permission bits used when k is written outside the
+// FS.
+func (k *key) exportMode() fs.FileMode {
+	if k.mode == 0 {
+		return 0o644
+	}
+	return k.mode
+}
+
+// Owner sets the ownership of a key, as written by WriteTar. Either the
+// numeric IDs or the names may be left unset.
+type Owner struct {
+	UID, GID     int
+	Uname, Gname string
+}
+
+func (o Owner) applyToKey(k *key) {
+	k.owner = o
+}
+
+// PAXRecords sets extended header records written with a key by WriteTar, such
+// as extended attributes under "SCHILY.xattr.". Records are added to those
+// set by earlier options. The map is copied.
+type PAXRecords map[string]string
+
+func (o PAXRecords) applyToKey(k *key) {
+	if len(o) == 0 {
+		return
+	}
+	if k.pax == nil {
+		k.pax = make(map[string]string, len(o))
+	}
+	maps.Copy(k.pax, o)
+}
+
+// Metadata sets arbitrary metadata on a key, such as the headers it was
+// fetched with, for callers to read back with GetMeta. Entries are added to
+// those set by earlier options. The map is copied.
+type Metadata map[string]string
+
+func (o Metadata) applyToKey(k *key) {
+	if len(o) == 0 {
+		return
+	}
+	if k.meta == nil {
+		k.meta = make(map[string]string, len(o))
+	}
+	maps.Copy(k.meta, o)
+}
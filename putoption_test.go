@@ -0,0 +1,34 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestMode(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("script", []byte("#!"), time.Now(), nil, Mode(fs.ModeDir|fs.ModeSetuid|0o755))
+	d.Put("plain", []byte("x"), time.Now(), nil)
+	if fi, err := d.Stat("script"); err != nil || fi.Mode() != fs.ModeSetuid|0o755 {
+		t.Errorf("Stat = %v, %v; want the permission bits only", fi.Mode(), err)
+	}
+	if m := d.keys.get("plain").exportMode(); m != 0o644 {
+		t.Errorf("exportMode = %v, want 0644", m)
+	}
+
+	src := fstest.MapFS{"bin/tool": {Data: []byte("x"), Mode: 0o700}}
+	hour := time.Hour
+	d.FulfillFrom(ComposeSource(src, &hour))
+	if fi, err := d.Stat("bin/tool"); err == nil {
+		t.Errorf("Stat fulfilled without StatFulfills: %v", fi)
+	}
+	d.ReadFile("bin/tool")
+	if fi, err := d.Stat("bin/tool"); err != nil || fi.Mode() != 0o700 {
+		t.Errorf("Stat = %v, %v; want the mode from the ModeSource", fi, err)
+	}
+}
@@ -0,0 +1,176 @@
+package gomemfs
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// A Quota is an FSOption limiting the keys beneath the directory Prefix to
+// MaxBytes of content and MaxKeys keys in total, where either limit is
+// ignored if it is not greater than zero. Elements of Prefix may be patterns
+// in the syntax of [path.Match], in which case each directory matching it
+// has a quota of its own: with Prefix "tenants/*" and MaxBytes 50<<20, every
+// tenant may hold 50 MB. Putting or fulfilling a key that would exceed a
+// quota fails with a *QuotaError. Setting a Quota replaces any earlier one
+// with the same Prefix, and a Quota without limits removes it.
+type Quota struct {
+	Prefix   string
+	MaxBytes int64
+	MaxKeys  int
+}
+
+func (fso Quota) applyTo(fs *FS) error {
+	fso.Prefix = strings.Trim(path.Clean(fso.Prefix), "/")
+	if _, err := path.Match(fso.Prefix, ""); err != nil {
+		return fmt.Errorf("invalid quota prefix %q: %w", fso.Prefix, err)
+	}
+	// copied, since a clone shares the map
+	qs := maps.Clone(fs.quotas)
+	if qs == nil {
+		qs = make(map[string]Quota)
+	}
+	if fso.MaxBytes <= 0 && fso.MaxKeys <= 0 {
+		delete(qs, fso.Prefix)
+	} else {
+		qs[fso.Prefix] = fso
+	}
+	fs.quotas = qs
+	return nil
+}
+
+// A QuotaError is returned, usually wrapped in an [fs.PathError], when storing
+// a key would exceed a Quota.
+type QuotaError struct {
+	// Quota is the quota that would be exceeded, and Dir the directory it
+	// applies to, which differs from Quota.Prefix if that is a pattern.
+	Quota Quota
+	Dir   string
+
+	// Bytes and Keys is the usage of Dir if the key were stored.
+	Bytes int64
+	Keys  int
+}
+
+func (e *QuotaError) Error() string {
+	if e.Quota.MaxKeys > 0 && e.Keys > e.Quota.MaxKeys {
+		return fmt.Sprintf("quota of %q exceeded: %d of %d keys", e.Dir, e.Keys, e.Quota.MaxKeys)
+	}
+	return fmt.Sprintf("quota of %q exceeded: %d of %d bytes", e.Dir, e.Bytes, e.Quota.MaxBytes)
+}
+
+// QuotaUsage reports the usage of a directory with a Quota.
+type QuotaUsage struct {
+	Quota Quota
+	Dir   string
+	Bytes int64
+	Keys  int
+}
+
+// QuotaUsage returns the usage of every directory that has a Quota and holds
+// at least one key, sorted by directory.
+func (d *FS) QuotaUsage() []QuotaUsage {
+	d.mu.Lock()
+	defer d.unlock()
+	usage := make(map[string]*QuotaUsage)
+	for _, q := range d.quotas {
+		for k := range d.quotaKeys(quotaRoot(q.Prefix)) {
+			dir, ok := d.quotaDir(q, k.name)
+			if !ok {
+				continue
+			}
+			u := usage[dir]
+			if u == nil {
+				u = &QuotaUsage{Quota: q, Dir: dir}
+				usage[dir] = u
+			}
+			u.Bytes += k.size()
+			u.Keys++
+		}
+	}
+	us := make([]QuotaUsage, 0, len(usage))
+	for _, dir := range slices.Sorted(maps.Keys(usage)) {
+		us = append(us, *usage[dir])
+	}
+	return us
+}
+
+// quotaDir returns the directory to which q applies for key name, if any.
+func (d *FS) quotaDir(q Quota, name string) (string, bool) {
+	pattern := q.Prefix
+	if d.caseInsensitive || d.casePreserving {
+		pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	}
+	if pattern == "." {
+		return ".", true
+	}
+	n := strings.Count(pattern, "/") + 1
+	elems := strings.SplitN(name, "/", n+1)
+	if len(elems) < n {
+		return "", false
+	}
+	dir := strings.Join(elems[:n], "/")
+	if ok, _ := path.Match(pattern, dir); !ok {
+		return "", false
+	}
+	return dir, true
+}
+
+// quotaRoot returns the directory beneath which every directory matching the
+// quota prefix pattern is found.
+func quotaRoot(pattern string) string {
+	elems := strings.Split(pattern, "/")
+	for i, e := range elems {
+		if strings.ContainsAny(e, `*?[\`) {
+			if i == 0 {
+				return "."
+			}
+			return strings.Join(elems[:i], "/")
+		}
+	}
+	return pattern
+}
+
+// quotaKeys returns an iterator over the keys held at or beneath dir, so that
+// only those are visited rather than every key.
+func (d *FS) quotaKeys(dir string) iter.Seq[*key] {
+	// must be called with fs.mu Locked
+	return func(yield func(*key) bool) {
+		if dir != "." {
+			if k := d.lookup(dir); k != nil && !yield(k) {
+				return
+			}
+		}
+		for n := range d.keys.under(d.fold(dir)) {
+			if k := d.lookup(n); k != nil && !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// checkQuota returns a *QuotaError if storing size bytes as key name would
+// exceed a Quota.
+func (d *FS) checkQuota(name string, size int64) error {
+	// must be called with fs.mu Locked
+	for _, q := range d.quotas {
+		dir, ok := d.quotaDir(q, name)
+		if !ok {
+			continue
+		}
+		e := &QuotaError{Quota: q, Dir: dir, Bytes: size, Keys: 1}
+		for k := range d.quotaKeys(dir) {
+			if d.fold(k.name) != d.fold(name) {
+				e.Bytes += k.size()
+				e.Keys++
+			}
+		}
+		if (q.MaxBytes > 0 && e.Bytes > q.MaxBytes) || (q.MaxKeys > 0 && e.Keys > q.MaxKeys) {
+			return e
+		}
+	}
+	return nil
+}
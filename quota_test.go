@@ -0,0 +1,54 @@
+package gomemfs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuota(t *testing.T) {
+	d, err := New(Quota{Prefix: "tenants/*", MaxBytes: 4, MaxKeys: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := d.Put("tenants/a/1", []byte("ab"), now, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Put("tenants/a/2", []byte("cd"), now, nil); err != nil {
+		t.Fatal(err)
+	}
+	var qe *QuotaError
+	if err := d.Put("tenants/a/3", []byte("e"), now, nil); !errors.As(err, &qe) || qe.Dir != "tenants/a" {
+		t.Errorf("Put over quota = %v", err)
+	}
+	// replacing a key counts only its new size
+	if err := d.Put("tenants/a/2", []byte("xy"), now, nil); err != nil {
+		t.Errorf("replacing a key = %v", err)
+	}
+	// every tenant has a quota of its own, and other keys have none
+	if err := d.Put("tenants/b/1", []byte("abcd"), now, nil); err != nil {
+		t.Error(err)
+	}
+	if err := d.Put("other", []byte("abcdefgh"), now, nil); err != nil {
+		t.Error(err)
+	}
+
+	u := d.QuotaUsage()
+	if len(u) != 2 || u[0].Dir != "tenants/a" || u[0].Bytes != 4 || u[0].Keys != 2 || u[1].Dir != "tenants/b" || u[1].Bytes != 4 {
+		t.Errorf("QuotaUsage = %+v", u)
+	}
+}
+
+func TestQuotaOnKeyNamedLikeDir(t *testing.T) {
+	d, err := New(Quota{Prefix: "a", MaxKeys: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Put("a", []byte("x"), time.Now(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Put("a/b", []byte("x"), time.Now(), nil); err == nil {
+		t.Error("key named like the quota directory was not counted")
+	}
+}
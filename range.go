@@ -0,0 +1,96 @@
+package gomemfs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+)
+
+// OpenRange opens key name, fulfilling it if needed, and returns a reader
+// limited to the length bytes of its content starting at off, as by
+// [io.NewSectionReader]. Offsets passed to Seek are relative to off. The
+// range may extend past the end of the content, in which case reads stop at
+// the end.
+func (d *FS) OpenRange(name string, off, length int64) (io.ReadSeeker, error) {
+	return d.openRangeIn(".", name, off, length)
+}
+
+// openRangeIn is OpenRange, resolving links within root.
+func (d *FS) openRangeIn(root, name string, off, length int64) (io.ReadSeeker, error) {
+	if off < 0 || length < 0 {
+		return nil, &fs.PathError{Op: "openrange", Path: name, Err: fs.ErrInvalid}
+	}
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "openrange", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+
+	k, _, err := d.get(context.Background(), root, n, fetchFulfill)
+	if err != nil {
+		return nil, &fs.PathError{Op: "openrange", Path: name, Err: err}
+	}
+	if k == nil {
+		return nil, &fs.PathError{Op: "openrange", Path: name, Err: errIsDir}
+	}
+	b, err := k.content()
+	if err != nil {
+		return nil, &fs.PathError{Op: "openrange", Path: name, Err: err}
+	}
+	r := io.NewSectionReader(bytes.NewReader(b), off, length)
+	if k.mapping != nil {
+		// keep the file mapped while r is reachable
+		return mappedReader{r, k.mapping}, nil
+	}
+	return r, nil
+}
+
+// mappedReader reads from a mapped file, which it keeps mapped.
+type mappedReader struct {
+	*io.SectionReader
+	m *mapping
+}
+
+// ReadAt reads len(p) bytes of the content of key name, starting at off, into
+// p, fulfilling the key if needed. It follows the contract of [io.ReaderAt],
+// returning [io.EOF] if fewer than len(p) bytes are available. Unlike reading
+// from a File, no File or reader is allocated, which makes it suitable for
+// many small random reads.
+func (d *FS) ReadAt(name string, p []byte, off int64) (int, error) {
+	return d.readAtIn(".", name, p, off)
+}
+
+// readAtIn is ReadAt, resolving links within root.
+func (d *FS) readAtIn(root, name string, p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, &fs.PathError{Op: "readat", Path: name, Err: fs.ErrInvalid}
+	}
+	n, err := d.normalize(name)
+	if err != nil {
+		return 0, &fs.PathError{Op: "readat", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+
+	k, _, err := d.get(context.Background(), root, n, fetchFulfill)
+	if err != nil {
+		return 0, &fs.PathError{Op: "readat", Path: name, Err: err}
+	}
+	if k == nil {
+		return 0, &fs.PathError{Op: "readat", Path: name, Err: errIsDir}
+	}
+	b, err := k.content()
+	if err != nil {
+		return 0, &fs.PathError{Op: "readat", Path: name, Err: err}
+	}
+	if off >= int64(len(b)) {
+		return 0, io.EOF
+	}
+	c := copy(p, b[off:])
+	if c < len(p) {
+		return c, io.EOF
+	}
+	return c, nil
+}
@@ -0,0 +1,64 @@
+package gomemfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestOpenRange(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a", []byte("0123456789"), time.Now(), nil)
+	r, err := d.OpenRange("a", 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := io.ReadAll(r); err != nil || string(b) != "23456" {
+		t.Errorf("ReadAll = %q, %v", b, err)
+	}
+	if off, err := r.Seek(1, io.SeekStart); err != nil || off != 1 {
+		t.Errorf("Seek = %d, %v", off, err)
+	}
+	if b, _ := io.ReadAll(r); string(b) != "3456" {
+		t.Errorf("ReadAll after Seek = %q", b)
+	}
+	r, err = d.OpenRange("a", 8, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(r); string(b) != "89" {
+		t.Errorf("ReadAll past the end = %q", b)
+	}
+	if _, err := d.OpenRange("a", -1, 1); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("OpenRange(-1) = %v, want fs.ErrInvalid", err)
+	}
+	if _, err := d.OpenRange("missing", 0, 1); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenRange(missing) = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestReadAt(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("a", []byte("0123456789"), time.Now(), nil)
+	p := make([]byte, 4)
+	if n, err := d.ReadAt("a", p, 3); err != nil || n != 4 || string(p) != "3456" {
+		t.Errorf("ReadAt = %d %q, %v", n, p, err)
+	}
+	if n, err := d.ReadAt("a", p, 8); err != io.EOF || n != 2 || string(p[:n]) != "89" {
+		t.Errorf("ReadAt at the end = %d %q, %v; want io.EOF", n, p[:n], err)
+	}
+	if n, err := d.ReadAt("a", p, 10); err != io.EOF || n != 0 {
+		t.Errorf("ReadAt past the end = %d, %v", n, err)
+	}
+	if _, err := d.ReadAt("a", p, -1); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("ReadAt(-1) = %v, want fs.ErrInvalid", err)
+	}
+}
@@ -0,0 +1,135 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is returned, usually wrapped in an [fs.PathError], when a key
+// is not fulfilled because a RateLimit or a Source created with RateLimited
+// allows no more fulfillments for now. The caller may try again later.
+var ErrRateLimited = errors.New("fulfillment rate limit exceeded")
+
+// A RateLimit is an FSOption limiting how many fulfillments an FS starts, so
+// that a burst of cache misses cannot overwhelm the sources behind it. Up to
+// Burst fulfillments may start at once, after which they are allowed at
+// PerSecond. A fulfillment over the limit fails with ErrRateLimited or, if
+// Wait is true, waits for its turn until the context passed to eg
+// FS.OpenContext is done. Keys already held are never limited. A RateLimit
+// with PerSecond not greater than zero removes the limit.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+	Wait      bool
+}
+
+func (fso RateLimit) applyTo(fs *FS) error {
+	if fso.PerSecond <= 0 {
+		fs.rateLimit = nil
+		return nil
+	}
+	fs.rateLimit = &rateLimiter{
+		bucket: newTokenBucket(fso.PerSecond, fso.Burst, fs.now()),
+		wait:   fso.Wait,
+	}
+	return nil
+}
+
+type rateLimiter struct {
+	bucket *tokenBucket
+	wait   bool
+}
+
+// limit waits for the RateLimit to allow fulfilling name. It returns the key
+// if another caller stored it while waiting.
+func (d *FS) limit(ctx context.Context, name string) (*key, error) {
+	// must be called with fs.mu Locked, which is released while waiting
+	for {
+		rl := d.rateLimit
+		if rl == nil {
+			return nil, nil
+		}
+		delay := rl.bucket.take(d.now())
+		if delay == 0 {
+			return nil, nil
+		}
+		if !rl.wait {
+			return nil, ErrRateLimited
+		}
+		d.mu.Unlock()
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			d.mu.Lock()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+		d.mu.Lock()
+		if k := d.lookup(name); k != nil {
+			return k, nil
+		}
+	}
+}
+
+// RateLimited returns a Source that passes calls to s at most perSecond
+// times a second, after an initial burst, and fails others with
+// ErrRateLimited. Use it to protect one fragile backend among several.
+func RateLimited(s Source, perSecond float64, burst int) Source {
+	return &rateLimitedSource{Source: s, bucket: newTokenBucket(perSecond, burst, time.Now())}
+}
+
+type rateLimitedSource struct {
+	Source
+	bucket *tokenBucket
+}
+
+// Fulfill implements Source.
+func (r *rateLimitedSource) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return r.FulfillContext(context.Background(), name)
+}
+
+// FulfillContext implements ContextSource.
+func (r *rateLimitedSource) FulfillContext(ctx context.Context, name string) ([]byte, *time.Time, *time.Time, error) {
+	if r.bucket.take(time.Now()) > 0 {
+		return nil, nil, nil, ErrRateLimited
+	}
+	return fulfillContext(ctx, r.Source, name)
+}
+
+// tokenBucket is a token bucket rate limiter.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int, now time.Time) *tokenBucket {
+	b := float64(max(burst, 1))
+	return &tokenBucket{rate: rate, burst: b, tokens: b, last: now}
+}
+
+// take takes a token if one is available and returns 0, or otherwise returns
+// how long it will be until one is.
+func (b *tokenBucket) take(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// CheckHealth implements HealthChecker if the wrapped Source does.
+func (r *rateLimitedSource) CheckHealth(ctx context.Context) error {
+	return checkHealth(ctx, r.Source)
+}
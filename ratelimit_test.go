@@ -0,0 +1,66 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }), RateLimit{PerSecond: 1, Burst: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return []byte(p), nil, nil, nil
+	})
+	d.Put("held", []byte("x"), now, nil)
+	for range 2 {
+		if _, err := d.ReadFile("a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.ReadFile("a"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("ReadFile over the burst = %v, want ErrRateLimited", err)
+	}
+	if _, err := d.ReadFile("held"); err != nil {
+		t.Errorf("ReadFile of a held key = %v", err)
+	}
+	now = now.Add(time.Second)
+	if _, err := d.ReadFile("a"); err != nil {
+		t.Errorf("ReadFile a second later = %v", err)
+	}
+
+	d.Set(RateLimit{PerSecond: 1, Burst: 1, Wait: true})
+	d.ReadFile("a")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.ReadFileContext(ctx, "a"); !errors.Is(err, context.Canceled) {
+		t.Errorf("waiting ReadFileContext = %v, want context.Canceled", err)
+	}
+
+	d.Set(RateLimit{})
+	for range 3 {
+		if _, err := d.ReadFile("a"); err != nil {
+			t.Errorf("ReadFile without a limit = %v", err)
+		}
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(RateLimited(Fulfiller(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		return []byte(p), nil, nil, nil
+	}), 0.001, 1))
+	if _, err := d.ReadFile("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.ReadFile("a"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("ReadFile = %v, want ErrRateLimited", err)
+	}
+}
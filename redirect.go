@@ -0,0 +1,32 @@
+package gomemfs
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+)
+
+// Redirect creates name as a redirect to target, replacing any existing key
+// called name. A Handler created with FileServer answers requests for name
+// with a redirect to target using the given status code, which must be one of
+// 301, 302, 303, 307 or 308; target may be an absolute URL or a path, which
+// is resolved as described for [http.Redirect]. Opening name through any
+// other interface yields an empty file.
+func (d *FS) Redirect(name, target string, code int) error {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return &fs.PathError{Op: "redirect", Path: name, Err: fmt.Errorf("status %d: %w", code, fs.ErrInvalid)}
+	}
+	if target == "" {
+		return &fs.PathError{Op: "redirect", Path: name, Err: fs.ErrInvalid}
+	}
+	return d.putKey(&key{
+		bytes:        []byte{},
+		name:         name,
+		modtime:      d.now(),
+		redirect:     target,
+		redirectCode: code,
+	})
+}
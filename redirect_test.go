@@ -0,0 +1,31 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"testing"
+)
+
+func TestRedirect(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Redirect("old", "/new", http.StatusOK); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Redirect with status 200 = %v, want fs.ErrInvalid", err)
+	}
+	if err := d.Redirect("old", "", http.StatusFound); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Redirect with no target = %v, want fs.ErrInvalid", err)
+	}
+	if err := d.Redirect("docs/old.html", "/docs/new.html", http.StatusMovedPermanently); err != nil {
+		t.Fatal(err)
+	}
+	w := get(FileServer(d), "/docs/old.html")
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/docs/new.html" {
+		t.Errorf("GET = %d, Location %q", w.Code, w.Header().Get("Location"))
+	}
+	if b, err := d.ReadFile("docs/old.html"); err != nil || len(b) != 0 {
+		t.Errorf("ReadFile = %q, %v; want an empty file", b, err)
+	}
+}
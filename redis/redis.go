@@ -0,0 +1,194 @@
+// Package redis provides a gomemfs RemoteStore backed by a Redis server (or a
+// compatible one such as Valkey), speaking RESP with only the standard library.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"strconv"
+	"time"
+)
+
+// A Store is a RemoteStore for the Redis server at an address. Its methods may
+// be called concurrently; it keeps up to MaxIdle connections open between
+// calls.
+type Store struct {
+	addr string
+	idle chan *conn
+
+	// Dialer opens connections. If nil, a zero net.Dialer is used.
+	Dialer *net.Dialer
+
+	// Password, if set, is sent with AUTH on each new connection, along with
+	// Username if that is set too.
+	Username string
+	Password string
+
+	// DB selects a database other than 0 on each new connection.
+	DB int
+}
+
+// MaxIdle is the number of idle connections a Store keeps open.
+const MaxIdle = 4
+
+// New returns a Store for the Redis server at addr, eg "localhost:6379". No
+// connection is made until the Store is used.
+func New(addr string) *Store {
+	return &Store{addr: addr, idle: make(chan *conn, MaxIdle)}
+}
+
+// An Error is an error reply from the server.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// Get implements gomemfs.RemoteStore.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.do(ctx, func(c *conn) (err error) {
+		value, err = c.command("GET", []byte(key))
+		if err == nil && value == nil {
+			return fs.ErrNotExist
+		}
+		return err
+	})
+	return value, err
+}
+
+// Set implements gomemfs.RemoteStore.
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.do(ctx, func(c *conn) error {
+		args := [][]byte{[]byte(key), value}
+		if ttl > 0 {
+			args = append(args, []byte("PX"), strconv.AppendInt(nil, max(ttl.Milliseconds(), 1), 10))
+		}
+		_, err := c.command("SET", args...)
+		return err
+	})
+}
+
+// Delete implements gomemfs.RemoteStore.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, func(c *conn) error {
+		_, err := c.command("DEL", []byte(key))
+		return err
+	})
+}
+
+// do calls f with a connection, which is returned to the idle pool unless f
+// fails for a reason other than a miss or an error reply.
+func (s *Store) do(ctx context.Context, f func(*conn) error) error {
+	var c *conn
+	select {
+	case c = <-s.idle:
+	default:
+		var err error
+		if c, err = s.dial(ctx); err != nil {
+			return err
+		}
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		c.SetDeadline(dl)
+	} else {
+		c.SetDeadline(time.Time{})
+	}
+	err := f(c)
+	var reply Error
+	if err != nil && !errors.Is(err, fs.ErrNotExist) && !errors.As(err, &reply) {
+		c.Close()
+		return err
+	}
+	select {
+	case s.idle <- c:
+	default:
+		c.Close()
+	}
+	return err
+}
+
+// dial opens a new connection, authenticating and selecting the DB.
+func (s *Store) dial(ctx context.Context) (*conn, error) {
+	d := s.Dialer
+	if d == nil {
+		d = &net.Dialer{}
+	}
+	nc, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, err
+	}
+	c := &conn{Conn: nc, r: bufio.NewReader(nc)}
+	if dl, ok := ctx.Deadline(); ok {
+		c.SetDeadline(dl)
+	}
+	if s.Password != "" {
+		args := [][]byte{[]byte(s.Password)}
+		if s.Username != "" {
+			args = append([][]byte{[]byte(s.Username)}, args...)
+		}
+		if _, err := c.command("AUTH", args...); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	if s.DB != 0 {
+		if _, err := c.command("SELECT", strconv.AppendInt(nil, int64(s.DB), 10)); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// command sends a command and reads its reply, which is returned if it is a
+// string. A nil reply is returned as nil.
+func (c *conn) command(name string, args ...[]byte) ([]byte, error) {
+	buf := fmt.Appendf(nil, "*%d\r\n$%d\r\n%s\r\n", len(args)+1, len(name), name)
+	for _, a := range args {
+		buf = fmt.Appendf(buf, "$%d\r\n", len(a))
+		buf = append(append(buf, a...), '\r', '\n')
+	}
+	if _, err := c.Write(buf); err != nil {
+		return nil, err
+	}
+
+	line, err := c.r.ReadSlice('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 {
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+	body := string(line[1 : len(line)-2])
+	switch line[0] {
+	case '+', ':':
+		return []byte(body), nil
+	case '-':
+		return nil, Error(body)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, fmt.Errorf("redis: unexpected reply %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		v := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, v); err != nil {
+			return nil, err
+		}
+		return v[:n], nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,185 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// server is a Redis speaking just enough RESP for a Store, recording the
+// commands it receives.
+type server struct {
+	mu       sync.Mutex
+	password string
+	values   map[string][]byte
+	cmds     []string
+}
+
+func newServer(t *testing.T, password string) (*server, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	s := &server{password: password, values: make(map[string][]byte)}
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(c)
+		}
+	}()
+	return s, l.Addr().String()
+}
+
+// readCommand reads a command sent as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	var n int
+	if _, err := fmt.Fscanf(r, "*%d\r\n", &n); err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		var size int
+		if _, err := fmt.Fscanf(r, "$%d\r\n", &size); err != nil {
+			return nil, err
+		}
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+	return args, nil
+}
+
+func (s *server) serve(c net.Conn) {
+	defer c.Close()
+	r := bufio.NewReader(c)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.cmds = append(s.cmds, strings.Join(args, " "))
+		switch args[0] {
+		case "AUTH":
+			if args[len(args)-1] != s.password {
+				io.WriteString(c, "-WRONGPASS invalid password\r\n")
+			} else {
+				io.WriteString(c, "+OK\r\n")
+			}
+		case "SELECT":
+			io.WriteString(c, "+OK\r\n")
+		case "GET":
+			if v, ok := s.values[args[1]]; ok {
+				fmt.Fprintf(c, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				io.WriteString(c, "$-1\r\n")
+			}
+		case "SET":
+			s.values[args[1]] = []byte(args[2])
+			io.WriteString(c, "+OK\r\n")
+		case "DEL":
+			_, ok := s.values[args[1]]
+			delete(s.values, args[1])
+			fmt.Fprintf(c, ":%d\r\n", map[bool]int{false: 0, true: 1}[ok])
+		default:
+			io.WriteString(c, "-ERR unknown command\r\n")
+		}
+		s.mu.Unlock()
+	}
+}
+
+func TestStore(t *testing.T) {
+	srv, addr := newServer(t, "hunter2")
+	s := New(addr)
+	s.Username, s.Password, s.DB = "app", "hunter2", 3
+	ctx := context.Background()
+	if _, err := s.Get(ctx, "a"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Get = %v, want fs.ErrNotExist", err)
+	}
+	value := []byte("line one\r\nline two")
+	if err := s.Set(ctx, "a", value, 1500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(ctx, "b", value, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Get(ctx, "a"); err != nil || string(got) != string(value) {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, "a"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Get after Delete = %v, want fs.ErrNotExist", err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	want := []string{
+		"AUTH app hunter2",
+		"SELECT 3",
+		"GET a",
+		"SET a " + string(value) + " PX 1500",
+		"SET b " + string(value),
+	}
+	for i, w := range want {
+		if srv.cmds[i] != w {
+			t.Errorf("command %d = %q, want %q", i, srv.cmds[i], w)
+		}
+	}
+	if n := strings.Count(strings.Join(srv.cmds, "\n"), "AUTH"); n != 1 {
+		t.Errorf("authenticated %d times, want the connection reused", n)
+	}
+}
+
+func TestStoreAuthError(t *testing.T) {
+	_, addr := newServer(t, "hunter2")
+	s := New(addr)
+	s.Password = "wrong"
+	var e Error
+	if _, err := s.Get(context.Background(), "a"); !errors.As(err, &e) || !strings.HasPrefix(string(e), "WRONGPASS") {
+		t.Errorf("Get = %v, want the error reply", err)
+	}
+}
+
+func TestCommandReplies(t *testing.T) {
+	for _, tc := range []struct {
+		reply string
+		want  string
+		err   bool
+	}{
+		{"+OK\r\n", "OK", false},
+		{":12\r\n", "12", false},
+		{"$3\r\nabc\r\n", "abc", false},
+		{"$-1\r\n", "", false},
+		{"-ERR nope\r\n", "", true},
+		{"*1\r\n", "", true},
+	} {
+		client, server := net.Pipe()
+		go func() {
+			readCommand(bufio.NewReader(server))
+			io.WriteString(server, tc.reply)
+			server.Close()
+		}()
+		c := &conn{Conn: client, r: bufio.NewReader(client)}
+		got, err := c.command("PING")
+		if string(got) != tc.want || (err != nil) != tc.err {
+			t.Errorf("reply %q = %q, %v", tc.reply, got, err)
+		}
+		client.Close()
+	}
+}
@@ -0,0 +1,32 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+)
+
+// errFulfillLoop is returned when fulfilling a key requires the key itself,
+// which would otherwise wait for its own flight forever.
+var errFulfillLoop = errors.New("key is needed to fulfill itself")
+
+// fulfilling is the context value passed to a ContextSource while it
+// fulfills name for fs, linked to the fulfillments it was called from.
+type fulfilling struct {
+	fs   *FS
+	name string
+	up   *fulfilling
+}
+
+type fulfillingKey struct{}
+
+// enter returns a copy of ctx that records that d is fulfilling name, or
+// errFulfillLoop if ctx already records that.
+func (d *FS) enter(ctx context.Context, name string) (context.Context, error) {
+	up, _ := ctx.Value(fulfillingKey{}).(*fulfilling)
+	for f := up; f != nil; f = f.up {
+		if f.fs == d && d.fold(f.name) == d.fold(name) {
+			return nil, errFulfillLoop
+		}
+	}
+	return context.WithValue(ctx, fulfillingKey{}, &fulfilling{fs: d, name: name, up: up}), nil
+}
@@ -0,0 +1,48 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestContextSourceReadsFS(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(ContextFulfiller(func(ctx context.Context, p string) ([]byte, *time.Time, *time.Time, error) {
+		switch p {
+		case "page.html":
+			layout, err := d.ReadFileContext(ctx, "layout.html")
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			return append(layout, "page"...), nil, nil, nil
+		case "layout.html":
+			return []byte("layout:"), nil, nil, nil
+		case "self":
+			b, err := d.ReadFileContext(ctx, "self")
+			return b, nil, nil, err
+		}
+		return nil, nil, nil, nil
+	}))
+	if b, err := d.ReadFile("page.html"); err != nil || string(b) != "layout:page" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := d.ReadFile("self")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, errFulfillLoop) {
+			t.Errorf("ReadFile(self) = %v, want errFulfillLoop", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a key needed to fulfill itself waited forever")
+	}
+}
@@ -0,0 +1,121 @@
+package gomemfs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxHeuristic caps how long a response without explicit freshness is cached.
+const maxHeuristic = 24 * time.Hour
+
+// Remote returns a ContextFulfiller that fetches each path with a GET request
+// for that path beneath base, using c, or [http.DefaultClient] if c is nil.
+// The modtime is taken from Last-Modified, and the expiry from the response's
+// freshness as a shared cache would compute it: s-maxage or max-age less any
+// Age, or else Expires. Responses marked no-store, no-cache or private are
+// served but not cached, and those with no freshness information are cached
+// for a tenth of the time since they were last modified (at most a day), or
+// not at all without a Last-Modified. This makes the FS a caching proxy in
+// front of an origin server. A 404 or 410 reply falls through to other
+// Fulfillers, and any other status than 200 is an error.
+func Remote(base *url.URL, c *http.Client) ContextFulfiller {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	return func(ctx context.Context, name string) ([]byte, *time.Time, *time.Time, error) {
+		u := base.JoinPath(name)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		resp, err := c.Do(req)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		defer resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound, http.StatusGone:
+			return nil, nil, nil, nil
+		default:
+			return nil, nil, nil, fmt.Errorf("cannot fetch %s: %s", u.Redacted(), resp.Status)
+		}
+		buf, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot read %s: %w", u.Redacted(), err)
+		}
+
+		now := time.Now()
+		mt := now
+		lm, lmErr := http.ParseTime(resp.Header.Get("Last-Modified"))
+		if lmErr == nil {
+			mt = lm
+		}
+		var expire time.Time
+		if fresh, ok := freshness(resp.Header, now); ok {
+			if fresh > 0 {
+				expire = now.Add(fresh)
+			}
+		} else if lmErr == nil && lm.Before(now) {
+			expire = now.Add(min(now.Sub(lm)/10, maxHeuristic))
+		}
+		return buf, &mt, &expire, nil
+	}
+}
+
+// freshness returns how much longer a response with headers h, received at
+// now, may be served from a shared cache. It returns false if h has no
+// freshness information, and zero if the response must not be cached.
+func freshness(h http.Header, now time.Time) (time.Duration, bool) {
+	var maxAge, sMaxAge = -1, -1
+	for _, v := range h.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(d), "=")
+			switch strings.ToLower(name) {
+			case "no-store", "no-cache", "private":
+				return 0, true
+			case "max-age":
+				maxAge = delta(arg)
+			case "s-maxage":
+				sMaxAge = delta(arg)
+			}
+		}
+	}
+	age := time.Duration(max(delta(h.Get("Age")), 0)) * time.Second
+	switch {
+	case sMaxAge >= 0:
+		return max(time.Duration(sMaxAge)*time.Second-age, 0), true
+	case maxAge >= 0:
+		return max(time.Duration(maxAge)*time.Second-age, 0), true
+	}
+	if v := h.Get("Expires"); v != "" {
+		exp, err := http.ParseTime(v)
+		if err != nil {
+			// an invalid Expires means already expired
+			return 0, true
+		}
+		// measure against the server's clock where possible
+		date, err := http.ParseTime(h.Get("Date"))
+		if err != nil {
+			date = now
+		}
+		return max(exp.Sub(date)-age, 0), true
+	}
+	return 0, false
+}
+
+// delta parses the delta-seconds of a Cache-Control directive or an Age
+// header, returning -1 if s is not valid.
+func delta(s string) int {
+	n, err := strconv.Atoi(strings.Trim(s, `"`))
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
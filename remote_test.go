@@ -0,0 +1,82 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFreshness(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		h     http.Header
+		fresh time.Duration
+		ok    bool
+	}{
+		{http.Header{}, 0, false},
+		{http.Header{"Cache-Control": {"public, max-age=60"}}, time.Minute, true},
+		{http.Header{"Cache-Control": {"max-age=60", "s-maxage=120"}}, 2 * time.Minute, true},
+		{http.Header{"Cache-Control": {"max-age=60"}, "Age": {"50"}}, 10 * time.Second, true},
+		{http.Header{"Cache-Control": {"max-age=60"}, "Age": {"90"}}, 0, true},
+		{http.Header{"Cache-Control": {"max-age=60, private"}}, 0, true},
+		{http.Header{"Cache-Control": {"no-store"}}, 0, true},
+		{http.Header{"Cache-Control": {"max-age=x"}}, 0, false},
+		{http.Header{"Expires": {"Thu, 01 Jan 2026 00:05:00 GMT"}}, 5 * time.Minute, true},
+		{http.Header{"Expires": {"Thu, 01 Jan 2026 00:05:00 GMT"}, "Date": {"Thu, 01 Jan 2026 00:04:00 GMT"}}, time.Minute, true},
+		{http.Header{"Expires": {"0"}}, 0, true},
+	} {
+		if fresh, ok := freshness(tc.h, now); fresh != tc.fresh || ok != tc.ok {
+			t.Errorf("freshness(%v) = %v, %v; want %v, %v", tc.h, fresh, ok, tc.fresh, tc.ok)
+		}
+	}
+}
+
+func TestRemote(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/origin/cached.css":
+			w.Header().Set("Cache-Control", "max-age=3600")
+			w.Header().Set("Last-Modified", "Thu, 01 Jan 2026 00:00:00 GMT")
+		case "/origin/private.html":
+			w.Header().Set("Cache-Control", "private")
+		case "/origin/gone":
+			w.WriteHeader(http.StatusGone)
+			return
+		case "/origin/broken":
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer srv.Close()
+	base, _ := url.Parse(srv.URL + "/origin")
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(Remote(base, srv.Client()))
+	for range 2 {
+		if b, err := d.ReadFile("cached.css"); err != nil || string(b) != "/origin/cached.css" {
+			t.Fatalf("ReadFile = %q, %v", b, err)
+		}
+		d.ReadFile("private.html")
+	}
+	if len(paths) != 3 {
+		t.Errorf("requests = %q, want cached.css once and private.html twice", paths)
+	}
+	if m, err := d.GetMeta("cached.css"); err != nil || !m.ModTime.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetMeta = %+v, %v", m, err)
+	}
+	if _, err := d.ReadFile("gone"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := d.ReadFile("broken"); err == nil || errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile = %v, want an error for the status", err)
+	}
+}
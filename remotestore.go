@@ -0,0 +1,117 @@
+package gomemfs
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io/fs"
+	"time"
+)
+
+// A RemoteStore is a cache shared between processes, such as Redis or
+// memcached, used with Share so that several instances of a service can share
+// the content fulfilled by any one of them. The memcache and redis
+// subpackages provide RemoteStores for those servers; other clients are
+// easily adapted.
+type RemoteStore interface {
+	// Get returns the value stored under key, or an error wrapping
+	// [fs.ErrNotExist] if there is none.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value under key for ttl, or without expiry if ttl is zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes the value stored under key, if any.
+	Delete(ctx context.Context, key string) error
+}
+
+// A SharedSource reads content through a RemoteStore before calling the
+// Source it wraps. It is created with Share.
+type SharedSource struct {
+	s      Source
+	r      RemoteStore
+	prefix string
+
+	// OnError, if not nil, is called with errors from the RemoteStore, which
+	// are otherwise ignored, so that an outage of the shared cache only costs
+	// the calls to the wrapped Source it would have saved.
+	OnError func(error)
+}
+
+// sharedHeader is the length of the modtime and expiry stored before the
+// content in a RemoteStore.
+const sharedHeader = 16
+
+// Share returns a SharedSource that looks up each path, prefixed with prefix,
+// in r first. If r does not have it, s is called and its content is written to
+// r along with its modtime, to expire with it. Content that is not cached, or
+// that expires immediately, is not written. Keys stored with Put are never
+// written, as they are not fulfilled.
+func Share(s Source, r RemoteStore, prefix string) *SharedSource {
+	return &SharedSource{s: s, r: r, prefix: prefix}
+}
+
+// Fulfill implements Source.
+func (ss *SharedSource) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return ss.FulfillContext(context.Background(), name)
+}
+
+// FulfillContext implements ContextSource.
+func (ss *SharedSource) FulfillContext(ctx context.Context, name string) ([]byte, *time.Time, *time.Time, error) {
+	key := ss.prefix + name
+	if v, err := ss.r.Get(ctx, key); err == nil && len(v) >= sharedHeader {
+		mt := time.Unix(0, int64(binary.BigEndian.Uint64(v)))
+		if e := int64(binary.BigEndian.Uint64(v[8:])); e == 0 {
+			forever := Forever
+			return v[sharedHeader:], &mt, &forever, nil
+		} else if expire := time.Unix(0, e); time.Now().Before(expire) {
+			return v[sharedHeader:], &mt, &expire, nil
+		}
+	} else if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		ss.report(err)
+	}
+
+	content, mt, expire, err := fulfillContext(ctx, ss.s, name)
+	if err != nil || content == nil || expire == nil {
+		return content, mt, expire, err
+	}
+	var ttl time.Duration
+	forever := expire.Equal(Forever)
+	if !forever {
+		if ttl = time.Until(*expire); ttl <= 0 {
+			return content, mt, expire, nil
+		}
+	}
+	v := make([]byte, sharedHeader+len(content))
+	stamp := time.Now()
+	if mt != nil {
+		stamp = *mt
+	}
+	binary.BigEndian.PutUint64(v, uint64(stamp.UnixNano()))
+	if !forever {
+		binary.BigEndian.PutUint64(v[8:], uint64(expire.UnixNano()))
+	}
+	copy(v[sharedHeader:], content)
+	if err := ss.r.Set(ctx, key, v, ttl); err != nil {
+		ss.report(err)
+	}
+	return content, mt, expire, nil
+}
+
+// Invalidate removes path from the RemoteStore, so that every instance
+// fulfills it from the wrapped Source next time. Call it along with FS.Expire
+// when content changes before it expires.
+func (ss *SharedSource) Invalidate(ctx context.Context, path string) error {
+	return ss.r.Delete(ctx, ss.prefix+path)
+}
+
+// CheckHealth implements HealthChecker if the wrapped Source does.
+func (ss *SharedSource) CheckHealth(ctx context.Context) error {
+	return checkHealth(ctx, ss.s)
+}
+
+func (ss *SharedSource) report(err error) {
+	if ss.OnError != nil {
+		ss.OnError(err)
+	}
+}
@@ -0,0 +1,119 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mapStore is a RemoteStore held in a map, which records but ignores ttls.
+type mapStore struct {
+	mu   sync.Mutex
+	m    map[string][]byte
+	ttls map[string]time.Duration
+	err  error
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{m: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (s *mapStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	v, ok := s.m[key]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return v, nil
+}
+
+func (s *mapStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.m[key], s.ttls[key] = value, ttl
+	return nil
+}
+
+func (s *mapStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, key)
+	return nil
+}
+
+func TestShare(t *testing.T) {
+	store := newMapStore()
+	calls := 0
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := Fulfiller(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		calls++
+		switch p {
+		case "live":
+			return []byte("live"), &mt, nil, nil
+		case "forever":
+			forever := Forever
+			return []byte("forever"), &mt, &forever, nil
+		}
+		expire := time.Now().Add(time.Hour)
+		return []byte(p), &mt, &expire, nil
+	})
+	instance := func() *FS {
+		d, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.FulfillFrom(Share(src, store, "site:"))
+		return d
+	}
+	a, b := instance(), instance()
+	for _, name := range []string{"page", "forever", "live"} {
+		a.ReadFile(name)
+	}
+	if _, ok := store.m["site:live"]; ok || len(store.m) != 2 {
+		t.Errorf("stored %d values, want all but the uncached one", len(store.m))
+	}
+	if store.ttls["site:forever"] != 0 || store.ttls["site:page"] <= 0 {
+		t.Errorf("ttls = %v", store.ttls)
+	}
+
+	for _, name := range []string{"page", "forever"} {
+		if got, err := b.ReadFile(name); err != nil || string(got) != name {
+			t.Errorf("ReadFile = %q, %v", got, err)
+		}
+		if m, err := b.GetMeta(name); err != nil || !m.ModTime.Equal(mt) {
+			t.Errorf("GetMeta = %+v, %v; want the stored modtime", m, err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("Source called %d times, want 3", calls)
+	}
+
+	ss := Share(src, store, "site:")
+	if err := ss.Invalidate(context.Background(), "page"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.m["site:page"]; ok {
+		t.Error("Invalidate did not remove the value")
+	}
+
+	down := errors.New("store down")
+	store.err = down
+	var reported []error
+	ss.OnError = func(err error) { reported = append(reported, err) }
+	if got, _, _, err := ss.Fulfill("page"); err != nil || string(got) != "page" {
+		t.Errorf("Fulfill = %q, %v; want the Source's content", got, err)
+	}
+	if len(reported) != 2 || !errors.Is(reported[0], down) {
+		t.Errorf("reported %v, want the failed Get and Set", reported)
+	}
+}
@@ -0,0 +1,62 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ReplaceAll atomically replaces every key in d with the keys currently held
+// by next, so that readers of d observe either the old set of keys or the new
+// one, never a mixture. This allows a new generation of content to be
+// assembled off to the side, in an FS created with New and populated with Put,
+// and then swapped in. Keys are renamed according to d's options, such as
+// CaseInsensitive, but not passed to its Normalizers, as their names were
+// already normalized by next. The new keys are stored as if by Put, subject to
+// eg MaxKeys and Quota; if one cannot be, d is left unchanged. Fulfillers and
+// options of next are not copied, and next is not modified.
+func (d *FS) ReplaceAll(next *FS) error {
+	if next == d {
+		return errors.New("cannot replace FS with itself")
+	}
+	ks := next.snapshot("")
+
+	names := make([]string, len(ks))
+	for i, k := range ks {
+		n, err := d.clean(k.name)
+		if err != nil {
+			return fmt.Errorf("cannot replace with key %q: %w", k.name, err)
+		}
+		names[i] = n
+	}
+
+	d.mu.Lock()
+	defer d.unlock()
+	if err := d.checkSealed("replace keys"); err != nil {
+		return err
+	}
+	// the new keys are checked against each other rather than the old ones,
+	// which are restored if any of them cannot be stored
+	old := d.keys
+	d.keys = keyIndex{}
+	for i, k := range ks {
+		c := k.clone(d)
+		c.name = names[i]
+		if err := d.admit(c); err != nil {
+			d.keys = old
+			return fmt.Errorf("cannot replace with key %q: %w", k.name, err)
+		}
+		c.stored = d.now()
+		d.keys.set(d.fold(c.name), c)
+	}
+	for _, k := range old.all() {
+		if k != nil {
+			d.retire(k)
+			d.emit(context.Background(), EventRemoved, k)
+		}
+	}
+	for _, k := range d.keys.all() {
+		d.emitPut(k, false)
+	}
+	return nil
+}
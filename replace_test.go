@@ -0,0 +1,53 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplaceAllAppliesLimits(t *testing.T) {
+	next, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	next.Put("a", []byte("a"), time.Now(), nil)
+	next.Put("b", []byte("b"), time.Now(), nil)
+
+	d, err := New(MaxKeys(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("old", []byte("old"), time.Now(), nil)
+	if err := d.ReplaceAll(next); !errors.Is(err, ErrFull) {
+		t.Errorf("ReplaceAll = %v, want ErrFull", err)
+	}
+	if !d.Exists("old") || d.Exists("a") {
+		t.Error("failed ReplaceAll changed the keys")
+	}
+}
+
+func TestReplaceAllEncrypts(t *testing.T) {
+	next, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	next.Put("a", []byte("plaintext"), time.Now(), nil)
+	d, err := New(EncryptContent(make([]byte, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ReplaceAll(next); err != nil {
+		t.Fatal(err)
+	}
+	d.mu.Lock()
+	k := d.lookup("a")
+	d.unlock()
+	if k == nil || k.aead == nil || bytes.Contains(k.bytes, []byte("plaintext")) {
+		t.Error("key stored unencrypted")
+	}
+	if b, err := d.ReadFile("a"); err != nil || string(b) != "plaintext" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+}
@@ -0,0 +1,33 @@
+package gomemfs
+
+import (
+	"context"
+	"maps"
+)
+
+type requestValuesKey struct{}
+
+// WithRequestValues returns a copy of ctx carrying vals, such as a tenant ID
+// or locale, for a ContextSource to read with RequestValues when the context
+// is passed to eg FS.OpenContext. Values set by an earlier call on ctx are
+// kept unless vals replaces them. This allows a Fulfiller to generate
+// different content for the same name, but note that the FS caches keys by
+// name alone: such content should be returned with a zero expire time, so
+// that it is not cached, or be kept apart with a Namespace per tenant.
+// Concurrent fulfillments of a name for contexts carrying request values are
+// never merged into one, as they are for other callers.
+func WithRequestValues(ctx context.Context, vals map[string]string) context.Context {
+	merged := maps.Clone(RequestValues(ctx))
+	if merged == nil {
+		merged = make(map[string]string, len(vals))
+	}
+	maps.Copy(merged, vals)
+	return context.WithValue(ctx, requestValuesKey{}, merged)
+}
+
+// RequestValues returns the values attached to ctx with WithRequestValues,
+// or nil if there are none. The map must not be modified.
+func RequestValues(ctx context.Context) map[string]string {
+	vals, _ := ctx.Value(requestValuesKey{}).(map[string]string)
+	return vals
+}
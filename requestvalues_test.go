@@ -0,0 +1,83 @@
+package gomemfs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRequestValues(t *testing.T) {
+	if RequestValues(context.Background()) != nil {
+		t.Error("RequestValues of a bare context is not nil")
+	}
+	ctx := WithRequestValues(context.Background(), map[string]string{"tenant": "a", "locale": "en"})
+	ctx2 := WithRequestValues(ctx, map[string]string{"tenant": "b"})
+	if v := RequestValues(ctx2); v["tenant"] != "b" || v["locale"] != "en" {
+		t.Errorf("RequestValues = %v, want tenant replaced and locale kept", v)
+	}
+	if v := RequestValues(ctx); v["tenant"] != "a" {
+		t.Errorf("parent RequestValues = %v, modified by a later call", v)
+	}
+
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(ContextFulfiller(func(ctx context.Context, p string) ([]byte, *time.Time, *time.Time, error) {
+		return []byte(RequestValues(ctx)["locale"] + ":" + p), nil, nil, nil
+	}))
+	if b, err := d.ReadFileContext(ctx, "greeting"); err != nil || string(b) != "en:greeting" {
+		t.Errorf("ReadFileContext = %q, %v", b, err)
+	}
+	fr := WithRequestValues(context.Background(), map[string]string{"locale": "fr"})
+	if b, err := d.ReadFileContext(fr, "greeting"); err != nil || string(b) != "fr:greeting" {
+		t.Errorf("ReadFileContext = %q, %v", b, err)
+	}
+}
+
+func TestRequestValuesNotShared(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var started sync.WaitGroup
+	started.Add(2)
+	both := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(both)
+	}()
+	d.FulfillFrom(ContextFulfiller(func(ctx context.Context, p string) ([]byte, *time.Time, *time.Time, error) {
+		started.Done()
+		// hold the fulfillment until the other tenant's has started too
+		select {
+		case <-both:
+		case <-time.After(time.Second):
+		}
+		var never time.Time
+		return []byte("secret of " + RequestValues(ctx)["tenant"]), nil, &never, nil
+	}))
+
+	var wg sync.WaitGroup
+	got := make(map[string]string)
+	var mu sync.Mutex
+	for _, tenant := range []string{"a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := WithRequestValues(context.Background(), map[string]string{"tenant": tenant})
+			b, err := d.ReadFileContext(ctx, "secret")
+			if err != nil {
+				t.Error(err)
+			}
+			mu.Lock()
+			got[tenant] = string(b)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if got["a"] != "secret of a" || got["b"] != "secret of b" {
+		t.Errorf("tenants read %q", got)
+	}
+}
@@ -0,0 +1,81 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+)
+
+// A RetryPolicy retries a Fulfiller that returns an error. As an FSOption it
+// applies to every Fulfiller of an FS; use Retry to apply it to one Source
+// only. A Fulfiller is called at most Attempts times, waiting Backoff before
+// the first retry and twice as long before each following one, up to
+// MaxBackoff if that is greater than zero. Retrying stops early when the
+// context of the fulfillment is done. Only errors for which Retryable
+// returns true are retried; if Retryable is nil, every error is retried
+// except those wrapping [fs.ErrNotExist].
+type RetryPolicy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Retryable  func(error) bool
+}
+
+func (fso RetryPolicy) applyTo(fs *FS) error {
+	fs.retry = fso
+	return nil
+}
+
+// Retry returns a Source that calls s according to p.
+func Retry(s Source, p RetryPolicy) Source {
+	return &retrySource{Source: s, p: p}
+}
+
+type retrySource struct {
+	Source
+	p RetryPolicy
+}
+
+// Fulfill implements Source.
+func (r *retrySource) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return r.p.fulfill(context.Background(), r.Source, name)
+}
+
+// FulfillContext implements ContextSource. Waiting stops when ctx is done.
+func (r *retrySource) FulfillContext(ctx context.Context, name string) ([]byte, *time.Time, *time.Time, error) {
+	return r.p.fulfill(ctx, r.Source, name)
+}
+
+func (p RetryPolicy) fulfill(ctx context.Context, s Source, name string) (content []byte, modtime, expire *time.Time, err error) {
+	wait := p.Backoff
+	for attempt := 1; ; attempt++ {
+		content, modtime, expire, err = fulfillContext(ctx, s, name)
+		if err == nil || attempt >= p.Attempts || !p.retryable(err) {
+			return content, modtime, expire, err
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return content, modtime, expire, err
+		case <-t.C:
+		}
+		wait *= 2
+		if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+			wait = p.MaxBackoff
+		}
+	}
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return !errors.Is(err, fs.ErrNotExist)
+}
+
+// CheckHealth implements HealthChecker if the wrapped Source does.
+func (r *retrySource) CheckHealth(ctx context.Context) error {
+	return checkHealth(ctx, r.Source)
+}
@@ -0,0 +1,69 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+// flaky returns a Fulfiller that fails with err until it has been called
+// fails times, counting its calls in calls.
+func flaky(calls *int, fails int, err error) Fulfiller {
+	return func(p string) ([]byte, *time.Time, *time.Time, error) {
+		*calls++
+		if *calls <= fails {
+			return nil, nil, nil, err
+		}
+		return []byte(p), nil, nil, nil
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	d, err := New(RetryPolicy{Attempts: 3, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	d.FulfillWith(flaky(&calls, 2, errors.New("transient")))
+	if b, err := d.ReadFile("a"); err != nil || string(b) != "a" || calls != 3 {
+		t.Errorf("ReadFile = %q, %v after %d calls", b, err, calls)
+	}
+
+	d, err = New(RetryPolicy{Attempts: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls = 0
+	d.FulfillWith(flaky(&calls, 5, fs.ErrNotExist))
+	d.ReadFile("b")
+	if calls != 1 {
+		t.Errorf("fs.ErrNotExist retried: %d calls", calls)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	permanent := errors.New("permanent")
+	calls := 0
+	d.FulfillFrom(Retry(flaky(&calls, 5, permanent), RetryPolicy{
+		Attempts:  3,
+		Retryable: func(err error) bool { return !errors.Is(err, permanent) },
+	}))
+	if _, err := d.ReadFile("a"); !errors.Is(err, permanent) || calls != 1 {
+		t.Errorf("ReadFile = %v after %d calls, want one call", err, calls)
+	}
+
+	d, err = New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls = 0
+	d.FulfillFrom(Retry(flaky(&calls, 5, errors.New("transient")), RetryPolicy{Attempts: 2}))
+	if _, err := d.ReadFile("b"); err == nil || calls != 2 {
+		t.Errorf("ReadFile = %v after %d calls, want Attempts calls", err, calls)
+	}
+}
@@ -0,0 +1,308 @@
+// Package s3 provides a gomemfs Source that fulfills paths from objects in an
+// S3-compatible object store, such as Amazon S3, MinIO or Cloudflare R2. It
+// uses only the standard library, signing requests with AWS Signature
+// Version 4.
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ironiridis/gomemfs"
+)
+
+// A Bucket describes where objects are fetched from, and how requests for them
+// are signed.
+type Bucket struct {
+	// Endpoint is the base URL of the object store, eg
+	// "https://s3.eu-west-1.amazonaws.com". If Name is set, requests use path
+	// style URLs, with the bucket as the first path element; otherwise the
+	// Endpoint is expected to address the bucket itself, as with virtual
+	// hosted URLs such as "https://bucket.s3.eu-west-1.amazonaws.com".
+	Endpoint string
+	Name     string
+
+	// Region is used to sign requests, eg "eu-west-1". Stores that do not
+	// have regions usually accept "us-east-1" or "auto".
+	Region string
+
+	// AccessKeyID and SecretAccessKey sign requests, along with SessionToken
+	// for temporary credentials. If AccessKeyID is empty, requests are not
+	// signed, which is enough for public buckets.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+
+	// Client sends requests. If nil, [http.DefaultClient] is used.
+	Client *http.Client
+}
+
+// An Option changes the behavior of a Source created with New.
+type Option interface {
+	applyTo(*Source)
+}
+
+// KeyPrefix is prepended to each path to form the key of its object, so that
+// with KeyPrefix("site/") the path "css/app.css" is fetched from the object
+// "site/css/app.css".
+type KeyPrefix string
+
+func (o KeyPrefix) applyTo(s *Source) {
+	s.prefix = string(o)
+}
+
+// TTLPolicy decides how long each object is cached, given its key and the
+// headers of the response that returned it, overriding the ttl passed to New.
+// A nil result means the object is cached without expiring, as if fulfilled
+// with an expire of gomemfs.Forever. This allows eg the
+// Cache-Control or Content-Type of an object to choose its lifetime.
+type TTLPolicy func(key string, h http.Header) *time.Duration
+
+func (o TTLPolicy) applyTo(s *Source) {
+	s.policy = o
+}
+
+// Conditional, if true, causes a Source to remember the ETag and content of
+// each object it fetches, and to fetch it again with If-None-Match once it
+// has expired from the FS. If the object has not changed, the store replies
+// without a body and the remembered content is used again, saving the
+// transfer. Remembered content is shared with the FS rather than copied, but
+// is kept until the object is found to be missing, even if the FS has
+// discarded it.
+type Conditional bool
+
+func (o Conditional) applyTo(s *Source) {
+	s.conditional = bool(o)
+}
+
+// A Source fulfills paths from the objects in a Bucket. It implements
+// gomemfs.ContextSource and gomemfs.StatSource, and is added to an FS with
+// FulfillFrom. Objects that do not exist fall through to other Sources.
+type Source struct {
+	b           Bucket
+	ttl         *time.Duration
+	policy      TTLPolicy
+	prefix      string
+	conditional bool
+
+	mu   sync.Mutex
+	seen map[string]object
+}
+
+// object is an object remembered for Conditional.
+type object struct {
+	etag    string
+	content []byte
+	modtime time.Time
+}
+
+// New returns a Source that fetches objects from b. If ttl is not nil,
+// objects expire at time.Now().Add(*ttl); otherwise they are not cached,
+// unless the FS has a DefaultTTL. A TTLPolicy overrides ttl.
+func New(b Bucket, ttl *time.Duration, opts ...Option) *Source {
+	s := &Source{b: b, ttl: ttl}
+	for _, o := range opts {
+		o.applyTo(s)
+	}
+	return s
+}
+
+// An Error is returned when the store replies with an unexpected status.
+// Code and Message are taken from the body of the reply, if it has them.
+type Error struct {
+	Key        string
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %s: %s", e.Key, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("s3: %s: %s: %s", e.Key, e.Code, e.Message)
+}
+
+// Fulfill implements gomemfs.Source.
+func (s *Source) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return s.FulfillContext(context.Background(), name)
+}
+
+// FulfillContext implements gomemfs.ContextSource, fetching the object for
+// name with a GET request that is cancelled with ctx.
+func (s *Source) FulfillContext(ctx context.Context, name string) ([]byte, *time.Time, *time.Time, error) {
+	key := s.prefix + name
+	req, err := s.request(ctx, http.MethodGet, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var prev object
+	if s.conditional {
+		s.mu.Lock()
+		prev = s.seen[key]
+		s.mu.Unlock()
+		if prev.etag != "" {
+			req.Header.Set("If-None-Match", prev.etag)
+		}
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var content []byte
+	var mt time.Time
+	switch resp.StatusCode {
+	case http.StatusOK:
+		content, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("s3: %s: %w", key, err)
+		}
+		mt = modtime(resp.Header)
+		if s.conditional {
+			s.remember(key, object{etag: resp.Header.Get("ETag"), content: content, modtime: mt})
+		}
+	case http.StatusNotModified:
+		if prev.etag == "" {
+			return nil, nil, nil, responseError(key, resp)
+		}
+		content, mt = prev.content, prev.modtime
+	case http.StatusNotFound:
+		if s.conditional {
+			s.remember(key, object{})
+		}
+		return nil, nil, nil, nil
+	default:
+		return nil, nil, nil, responseError(key, resp)
+	}
+
+	if s.policy != nil {
+		ttl := s.policy(key, resp.Header)
+		if ttl == nil {
+			forever := gomemfs.Forever
+			return content, &mt, &forever, nil
+		}
+		expire := time.Now().Add(*ttl)
+		return content, &mt, &expire, nil
+	}
+	if s.ttl != nil {
+		expire := time.Now().Add(*s.ttl)
+		return content, &mt, &expire, nil
+	}
+	return content, &mt, nil, nil
+}
+
+// Stat implements gomemfs.StatSource, describing the object for name with a
+// HEAD request, so that its content is only fetched when it is opened.
+func (s *Source) Stat(name string) (fs.FileInfo, error) {
+	key := s.prefix + name
+	req, err := s.request(context.Background(), http.MethodHead, key)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		size, _ := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+		return &objectInfo{name: path.Base(name), size: size, modtime: modtime(resp.Header)}, nil
+	case http.StatusNotFound:
+		return nil, nil
+	}
+	return nil, &Error{Key: key, StatusCode: resp.StatusCode}
+}
+
+// remember records o as the last seen version of key, or forgets key if o is
+// empty.
+func (s *Source) remember(key string, o object) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if o.etag == "" {
+		delete(s.seen, key)
+		return
+	}
+	if s.seen == nil {
+		s.seen = make(map[string]object)
+	}
+	s.seen[key] = o
+}
+
+func (s *Source) client() *http.Client {
+	if s.b.Client != nil {
+		return s.b.Client
+	}
+	return http.DefaultClient
+}
+
+// request returns a signed request for the object key.
+func (s *Source) request(ctx context.Context, method, key string) (*http.Request, error) {
+	u, err := url.Parse(s.b.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("s3: invalid endpoint: %w", err)
+	}
+	p := strings.TrimSuffix(u.Path, "/")
+	if s.b.Name != "" {
+		p += "/" + s.b.Name
+	}
+	p += "/" + key
+	u.Path, u.RawPath = p, escape(p)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.b.AccessKeyID != "" {
+		s.sign(req, time.Now())
+	}
+	return req, nil
+}
+
+// modtime returns the Last-Modified time from h, or the current time if it is
+// missing or invalid.
+func modtime(h http.Header) time.Time {
+	if t, err := http.ParseTime(h.Get("Last-Modified")); err == nil {
+		return t
+	}
+	return time.Now()
+}
+
+// responseError returns an Error for resp, reading the code and message of an
+// S3 error document from its body.
+func responseError(key string, resp *http.Response) error {
+	e := &Error{Key: key, StatusCode: resp.StatusCode}
+	var doc struct {
+		Code    string
+		Message string
+	}
+	if xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&doc) == nil {
+		e.Code, e.Message = doc.Code, doc.Message
+	}
+	return e
+}
+
+// objectInfo describes an object for Stat.
+type objectInfo struct {
+	name    string
+	size    int64
+	modtime time.Time
+}
+
+func (fi *objectInfo) Name() string       { return fi.name }
+func (fi *objectInfo) Size() int64        { return fi.size }
+func (fi *objectInfo) Mode() fs.FileMode  { return 0o444 }
+func (fi *objectInfo) ModTime() time.Time { return fi.modtime }
+func (fi *objectInfo) IsDir() bool        { return false }
+func (fi *objectInfo) Sys() any           { return nil }
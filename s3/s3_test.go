@@ -0,0 +1,166 @@
+package s3
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ironiridis/gomemfs"
+)
+
+// store serves objects under /bucket/, replying to If-None-Match with 304 if
+// the ETag matches, and records the requests it receives.
+type store struct {
+	objects map[string]string
+	reqs    []*http.Request
+}
+
+func (s *store) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.reqs = append(s.reqs, r)
+	body, ok := s.objects[r.URL.Path]
+	switch {
+	case r.URL.Path == "/bucket/denied":
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>"))
+		return
+	case !ok:
+		http.NotFound(w, r)
+		return
+	}
+	etag := `"` + body + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", "Thu, 01 Jan 2026 00:00:00 GMT")
+	w.Write([]byte(body))
+}
+
+func newStore(t *testing.T) (*store, Bucket) {
+	s := &store{objects: map[string]string{
+		"/bucket/site/app.css": "body{}",
+		"/bucket/site/a b.txt": "spaced",
+	}}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return s, Bucket{Endpoint: srv.URL, Name: "bucket", Region: "us-east-1", Client: srv.Client()}
+}
+
+func TestSource(t *testing.T) {
+	st, b := newStore(t)
+	d, err := gomemfs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := time.Hour
+	d.FulfillFrom(New(b, &hour, KeyPrefix("site/")))
+	got, err := d.ReadFile("app.css")
+	if err != nil || string(got) != "body{}" {
+		t.Fatalf("ReadFile = %q, %v", got, err)
+	}
+	m, err := d.GetMeta("app.css")
+	if err != nil || m.Expire == nil || !m.ModTime.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("GetMeta = %+v, %v", m, err)
+	}
+	if got, err := d.ReadFile("a b.txt"); err != nil || string(got) != "spaced" {
+		t.Errorf("ReadFile = %q, %v", got, err)
+	}
+	if st.reqs[0].Header.Get("Authorization") != "" {
+		t.Error("request without credentials was signed")
+	}
+	if _, err := d.ReadFile("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile = %v, want fs.ErrNotExist", err)
+	}
+	var e *Error
+	d.FulfillFrom(New(b, nil))
+	if _, err := d.ReadFile("denied"); !errors.As(err, &e) || e.StatusCode != http.StatusForbidden || e.Code != "AccessDenied" {
+		t.Errorf("ReadFile = %v, want an *Error with the code of the reply", err)
+	}
+}
+
+func TestConditional(t *testing.T) {
+	st, b := newStore(t)
+	d, err := gomemfs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(New(b, nil, KeyPrefix("site/"), Conditional(true)))
+	for range 2 {
+		if got, err := d.ReadFile("app.css"); err != nil || string(got) != "body{}" {
+			t.Fatalf("ReadFile = %q, %v", got, err)
+		}
+	}
+	if len(st.reqs) != 2 || st.reqs[1].Header.Get("If-None-Match") != `"body{}"` {
+		t.Errorf("second request did not revalidate")
+	}
+}
+
+func TestTTLPolicy(t *testing.T) {
+	_, b := newStore(t)
+	d, err := gomemfs.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillFrom(New(b, nil, KeyPrefix("site/"), TTLPolicy(func(key string, h http.Header) *time.Duration {
+		return nil
+	})))
+	d.ReadFile("app.css")
+	if m, err := d.GetMeta("app.css"); err != nil || m.Expire != nil {
+		t.Errorf("GetMeta = %+v, %v; want cached without expiring", m, err)
+	}
+}
+
+func TestStat(t *testing.T) {
+	st, b := newStore(t)
+	src := New(b, nil, KeyPrefix("site/"))
+	fi, err := src.Stat("app.css")
+	if err != nil || fi.Name() != "app.css" || fi.Size() != int64(len("body{}")) {
+		t.Fatalf("Stat = %v, %v", fi, err)
+	}
+	if st.reqs[0].Method != http.MethodHead {
+		t.Errorf("Stat sent %s, want HEAD", st.reqs[0].Method)
+	}
+	if fi, err := src.Stat("missing"); fi != nil || err != nil {
+		t.Errorf("Stat = %v, %v; want nil, nil", fi, err)
+	}
+}
+
+func TestSign(t *testing.T) {
+	src := New(Bucket{
+		Endpoint:        "https://s3.eu-west-1.amazonaws.com",
+		Name:            "bucket",
+		Region:          "eu-west-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		SessionToken:    "token",
+	}, nil)
+	req, err := src.request(t.Context(), http.MethodGet, "a b/c+d.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.EscapedPath() != "/bucket/a%20b/c%2Bd.txt" {
+		t.Errorf("path = %q", req.URL.EscapedPath())
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	src.sign(req, now)
+	auth := req.Header.Get("Authorization")
+	want := "AWS4-HMAC-SHA256 Credential=AKID/20260102/eu-west-1/s3/aws4_request, " +
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature="
+	if !strings.HasPrefix(auth, want) || len(auth) != len(want)+64 {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if req.Header.Get("X-Amz-Date") != "20260102T030405Z" || req.Header.Get("X-Amz-Security-Token") != "token" {
+		t.Errorf("headers = %v", req.Header)
+	}
+	first := auth
+	src.b.SecretAccessKey = "other"
+	src.sign(req, now)
+	if req.Header.Get("Authorization") == first {
+		t.Error("signature does not depend on the secret")
+	}
+}
@@ -0,0 +1,87 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// emptySHA256 is the hex SHA-256 digest of an empty payload, which is all this
+// package sends.
+const emptySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds an AWS Signature Version 4 Authorization header to req, which must
+// not have a body, signed at now.
+func (s *Source) sign(req *http.Request, now time.Time) {
+	now = now.UTC()
+	stamp := now.Format("20060102T150405Z")
+	date := stamp[:8]
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", emptySHA256)
+	if s.b.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.b.SessionToken)
+	}
+
+	// the headers signed are only those set above, plus host
+	names := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := []string{req.URL.Host, emptySHA256, stamp}
+	if s.b.SessionToken != "" {
+		names = append(names, "x-amz-security-token")
+		values = append(values, s.b.SessionToken)
+	}
+	var headers strings.Builder
+	for i, n := range names {
+		headers.WriteString(n + ":" + values[i] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		headers.String(),
+		signed,
+		emptySHA256,
+	}, "\n")
+	scope := date + "/" + s.b.Region + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	k := mac([]byte("AWS4"+s.b.SecretAccessKey), date)
+	k = mac(k, s.b.Region)
+	k = mac(k, "s3")
+	k = mac(k, "aws4_request")
+	sig := hex.EncodeToString(mac(k, toSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+s.b.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+sig)
+}
+
+func mac(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// escape encodes p as a canonical URI for signing: every byte other than an
+// unreserved character or a slash is percent-encoded.
+func escape(p string) string {
+	const hexdigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~', c == '/':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hexdigits[c>>4])
+			b.WriteByte(hexdigits[c&15])
+		}
+	}
+	return b.String()
+}
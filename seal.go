@@ -0,0 +1,49 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSealed is returned when trying to modify an FS after Seal was called.
+var ErrSealed = errors.New("FS is sealed")
+
+// SealPanics, if true, causes any attempt to modify a sealed FS to panic
+// instead of returning ErrSealed.
+type SealPanics bool
+
+func (fso SealPanics) applyTo(fs *FS) error {
+	fs.sealPanics = bool(fso)
+	return nil
+}
+
+// Seal makes the FS read-only. Afterwards Put, Set, FulfillWith, Expire,
+// FlushExpired and ReplaceAll fail with ErrSealed (or panic, see SealPanics).
+// Fulfillers that were added before Seal still run, but their results are no
+// longer cached, and expired keys are reported as missing but not removed, so
+// the set of keys never changes. Seal cannot be undone, but a Clone of a
+// sealed FS is not sealed.
+func (d *FS) Seal() {
+	d.mu.Lock()
+	defer d.unlock()
+	d.sealed = true
+}
+
+// Sealed reports whether Seal has been called.
+func (d *FS) Sealed() bool {
+	d.mu.Lock()
+	defer d.unlock()
+	return d.sealed
+}
+
+func (d *FS) checkSealed(op string) error {
+	// must be called with fs.mu Locked
+	if !d.sealed {
+		return nil
+	}
+	err := fmt.Errorf("cannot %s: %w", op, ErrSealed)
+	if d.sealPanics {
+		panic(err)
+	}
+	return err
+}
@@ -0,0 +1,69 @@
+package gomemfs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSeal(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.FulfillWith(func(p string) ([]byte, *time.Time, *time.Time, error) {
+		expire := now.Add(time.Hour)
+		return []byte(p), nil, &expire, nil
+	})
+	soon := now.Add(time.Minute)
+	d.Put("a", []byte("a"), now, nil)
+	d.Put("soon", []byte("soon"), now, &soon)
+	d.Seal()
+	if !d.Sealed() {
+		t.Fatal("Sealed = false")
+	}
+
+	for op, err := range map[string]error{
+		"Put":          d.Put("b", nil, now, nil),
+		"Expire":       d.Expire("a"),
+		"Set":          d.Set(DefaultTTL(time.Hour)),
+		"FulfillWith":  d.FulfillWith(nil),
+		"FlushExpired": d.FlushExpired(),
+	} {
+		if !errors.Is(err, ErrSealed) {
+			t.Errorf("%s = %v, want ErrSealed", op, err)
+		}
+	}
+	if b, err := d.ReadFile("x"); err != nil || string(b) != "x" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if d.Exists("x") {
+		t.Error("fulfilled content cached by a sealed FS")
+	}
+	now = now.Add(time.Hour)
+	if d.Exists("soon") {
+		t.Error("expired key reported by a sealed FS")
+	}
+	if _, ok := d.keys.lookup("soon"); !ok {
+		t.Error("expired key removed from a sealed FS")
+	}
+	if c := d.Clone(); c.Sealed() {
+		t.Error("Clone of a sealed FS is sealed")
+	}
+}
+
+func TestSealPanics(t *testing.T) {
+	d, err := New(SealPanics(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Seal()
+	defer func() {
+		if e, ok := recover().(error); !ok || !errors.Is(e, ErrSealed) {
+			t.Errorf("recovered %v, want ErrSealed", e)
+		}
+	}()
+	d.Put("a", nil, time.Now(), nil)
+	t.Error("Put did not panic")
+}
@@ -0,0 +1,261 @@
+package gomemfs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The snapshot format written by Save is a magic string followed by a
+// sequence of entries. Each entry is a sequence of fields, each written as a
+// uvarint tag, a uvarint length and that many bytes, and is terminated by a
+// zero tag. Readers skip fields with tags they do not know, so fields can be
+// added without breaking older snapshots.
+const snapshotMagic = "gomemfs\x00snap\x01"
+
+const (
+	fieldEnd = iota
+	fieldName
+	fieldContent
+	fieldModTime
+	fieldExpire
+	fieldLink
+	fieldRedirect
+	fieldRedirectCode
+	fieldMode
+	fieldUID
+	fieldGID
+	fieldUname
+	fieldGname
+	fieldPAX
+	fieldMeta
+)
+
+// ErrBadSnapshot is returned by Load when its input is not a valid snapshot.
+var ErrBadSnapshot = errors.New("invalid snapshot")
+
+// Save writes every key currently held by the FS to w in a compact binary
+// format that can be read back with Load, eg to keep a warmed cache across
+// process restarts. Nothing is fulfilled.
+func (d *FS) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(snapshotMagic); err != nil {
+		return fmt.Errorf("cannot write snapshot: %w", err)
+	}
+	var s snapshotWriter
+	for _, k := range d.snapshot("") {
+		b, err := k.content()
+		if err != nil {
+			return fmt.Errorf("cannot write snapshot: %w", err)
+		}
+		s.reset()
+		s.field(fieldName, []byte(k.name))
+		s.time(fieldModTime, k.modtime)
+		if k.expire != nil {
+			s.time(fieldExpire, *k.expire)
+		}
+		if k.mode != 0 {
+			s.field(fieldMode, binary.AppendUvarint(nil, uint64(k.mode)))
+		}
+		if k.owner.UID != 0 {
+			s.field(fieldUID, binary.AppendUvarint(nil, uint64(k.owner.UID)))
+		}
+		if k.owner.GID != 0 {
+			s.field(fieldGID, binary.AppendUvarint(nil, uint64(k.owner.GID)))
+		}
+		if k.owner.Uname != "" {
+			s.field(fieldUname, []byte(k.owner.Uname))
+		}
+		if k.owner.Gname != "" {
+			s.field(fieldGname, []byte(k.owner.Gname))
+		}
+		for _, r := range slices.Sorted(maps.Keys(k.pax)) {
+			// a record is its key and value separated by a NUL, which
+			// neither may contain
+			s.field(fieldPAX, []byte(r+"\x00"+k.pax[r]))
+		}
+		for _, m := range slices.Sorted(maps.Keys(k.meta)) {
+			// metadata may contain anything, so the key is length-prefixed
+			b := binary.AppendUvarint(nil, uint64(len(m)))
+			s.field(fieldMeta, append(append(b, m...), k.meta[m]...))
+		}
+		if k.link != "" {
+			s.field(fieldLink, []byte(k.link))
+		}
+		if k.redirect != "" {
+			s.field(fieldRedirect, []byte(k.redirect))
+			s.field(fieldRedirectCode, binary.AppendUvarint(nil, uint64(k.redirectCode)))
+		}
+		s.uvarint(fieldContent)
+		s.uvarint(uint64(len(b)))
+		if _, err := bw.Write(s.buf); err != nil {
+			return fmt.Errorf("cannot write snapshot of %q: %w", k.name, err)
+		}
+		if _, err := bw.Write(b); err != nil {
+			return fmt.Errorf("cannot write snapshot of %q: %w", k.name, err)
+		}
+		if err := bw.WriteByte(fieldEnd); err != nil {
+			return fmt.Errorf("cannot write snapshot of %q: %w", k.name, err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("cannot write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads a snapshot written by Save from r and puts every key in it into
+// the FS, replacing keys of the same name. Keys that have expired since the
+// snapshot was saved are skipped. If an error occurs, the keys read before it
+// have already been put.
+func (d *FS) Load(r io.Reader) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != snapshotMagic {
+		return fmt.Errorf("cannot load snapshot: %w", ErrBadSnapshot)
+	}
+
+	now := d.now()
+	for {
+		k, err := readSnapshotEntry(br)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot load snapshot: %w", err)
+		}
+		if k.expire != nil && now.After(*k.expire) {
+			continue
+		}
+		// names in a snapshot were normalized by the FS that saved it
+		name := k.name
+		if k.name, err = d.clean(name); err != nil {
+			return fmt.Errorf("cannot load snapshot key %q: %w", name, err)
+		}
+		if err := d.putNamed(name, k); err != nil {
+			return err
+		}
+	}
+}
+
+func readSnapshotEntry(br *bufio.Reader) (*key, error) {
+	k := &key{}
+	var named, first = false, true
+	for {
+		tag, err := binary.ReadUvarint(br)
+		if err == io.EOF && first {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBadSnapshot, err)
+		}
+		first = false
+		if tag == fieldEnd {
+			break
+		}
+		size, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrBadSnapshot, err)
+		}
+		var buf bytes.Buffer
+		if n, err := io.CopyN(&buf, br, int64(size)); err != nil || n != int64(size) {
+			return nil, fmt.Errorf("%w: truncated field", ErrBadSnapshot)
+		}
+		switch tag {
+		case fieldName:
+			k.name, named = buf.String(), true
+		case fieldContent:
+			k.bytes = buf.Bytes()
+		case fieldModTime:
+			if err := k.modtime.UnmarshalBinary(buf.Bytes()); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrBadSnapshot, err)
+			}
+		case fieldExpire:
+			var t time.Time
+			if err := t.UnmarshalBinary(buf.Bytes()); err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrBadSnapshot, err)
+			}
+			k.expire = &t
+		case fieldLink:
+			k.link = buf.String()
+		case fieldMode:
+			mode, n := binary.Uvarint(buf.Bytes())
+			if n <= 0 {
+				return nil, fmt.Errorf("%w: invalid mode", ErrBadSnapshot)
+			}
+			Mode(mode).applyToKey(k)
+		case fieldUID, fieldGID:
+			id, n := binary.Uvarint(buf.Bytes())
+			if n <= 0 {
+				return nil, fmt.Errorf("%w: invalid owner", ErrBadSnapshot)
+			}
+			if tag == fieldUID {
+				k.owner.UID = int(id)
+			} else {
+				k.owner.GID = int(id)
+			}
+		case fieldUname:
+			k.owner.Uname = buf.String()
+		case fieldGname:
+			k.owner.Gname = buf.String()
+		case fieldPAX:
+			r, v, ok := strings.Cut(buf.String(), "\x00")
+			if !ok {
+				return nil, fmt.Errorf("%w: invalid PAX record", ErrBadSnapshot)
+			}
+			PAXRecords{r: v}.applyToKey(k)
+		case fieldMeta:
+			b := buf.Bytes()
+			l, n := binary.Uvarint(b)
+			if n <= 0 || l > uint64(len(b)-n) {
+				return nil, fmt.Errorf("%w: invalid metadata", ErrBadSnapshot)
+			}
+			Metadata{string(b[n : n+int(l)]): string(b[n+int(l):])}.applyToKey(k)
+		case fieldRedirect:
+			k.redirect = buf.String()
+		case fieldRedirectCode:
+			code, n := binary.Uvarint(buf.Bytes())
+			if n <= 0 {
+				return nil, fmt.Errorf("%w: invalid redirect code", ErrBadSnapshot)
+			}
+			k.redirectCode = int(code)
+		}
+	}
+	if !named {
+		return nil, fmt.Errorf("%w: entry without name", ErrBadSnapshot)
+	}
+	if k.bytes == nil && k.link == "" {
+		k.bytes = []byte{}
+	}
+	return k, nil
+}
+
+type snapshotWriter struct {
+	buf []byte
+}
+
+func (s *snapshotWriter) reset() {
+	s.buf = s.buf[:0]
+}
+
+func (s *snapshotWriter) uvarint(v uint64) {
+	s.buf = binary.AppendUvarint(s.buf, v)
+}
+
+func (s *snapshotWriter) field(tag uint64, b []byte) {
+	s.uvarint(tag)
+	s.uvarint(uint64(len(b)))
+	s.buf = append(s.buf, b...)
+}
+
+func (s *snapshotWriter) time(tag uint64, t time.Time) {
+	b, _ := t.MarshalBinary()
+	s.field(tag, b)
+}
@@ -0,0 +1,55 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	later, soon := now.Add(time.Hour), now.Add(time.Minute)
+	d.Put("a", []byte("a"), now, &later, Mode(0o600))
+	d.Put("soon", []byte("soon"), now, &soon)
+	d.Put("empty", nil, now, nil)
+
+	var buf bytes.Buffer
+	if err := d.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	snap := buf.Bytes()
+
+	now = now.Add(30 * time.Minute)
+	l, err := New(Clock(func() time.Time { return now.Add(time.Minute) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Load(bytes.NewReader(snap)); err != nil {
+		t.Fatal(err)
+	}
+	m, err := l.GetMeta("a")
+	if err != nil || m.Mode != 0o600 || m.Expire == nil || !m.Expire.Equal(later) || !m.ModTime.Equal(d.keys.get("a").modtime) {
+		t.Errorf("GetMeta = %+v, %v", m, err)
+	}
+	if b, err := l.ReadFile("a"); err != nil || string(b) != "a" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+	if !l.Exists("empty") {
+		t.Error("empty key not loaded")
+	}
+	if _, ok := l.keys.lookup("soon"); ok {
+		t.Error("expired key loaded")
+	}
+
+	if err := l.Load(bytes.NewReader([]byte("not a snapshot"))); !errors.Is(err, ErrBadSnapshot) {
+		t.Errorf("Load = %v, want ErrBadSnapshot", err)
+	}
+	if err := l.Load(bytes.NewReader(snap[:len(snap)-3])); err == nil {
+		t.Error("Load of a truncated snapshot succeeded")
+	}
+}
@@ -8,18 +8,40 @@ import (
 
 type FileStat struct {
 	k *key
+
+	// dir is set when the key is the index file of directory dir, in which
+	// case the FileStat describes the directory.
+	dir string
 }
 
 func (s FileStat) Name() string {
+	if s.dir != "" {
+		return path.Base(s.dir)
+	}
 	return path.Base(s.k.name)
 }
 
 func (s FileStat) Size() int64 {
-	return int64(len(s.k.bytes))
+	if s.k.info != nil {
+		return s.k.info.Size()
+	}
+	if s.k.link != "" {
+		return int64(len(s.k.link))
+	}
+	return s.k.size()
 }
 
 func (s FileStat) Mode() fs.FileMode {
-	return fs.FileMode(0) // "regular"
+	if s.dir != "" {
+		return fs.ModeDir
+	}
+	if s.k.link != "" {
+		return fs.ModeSymlink
+	}
+	if s.k.info != nil {
+		return s.k.info.Mode()
+	}
+	return s.k.mode // "regular"
 }
 
 func (s FileStat) ModTime() time.Time {
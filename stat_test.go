@@ -0,0 +1,56 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// slowStat is a StatSource whose Stat blocks until release is closed.
+type slowStat struct {
+	release chan struct{}
+	stats   atomic.Int32
+}
+
+func (s *slowStat) Fulfill(name string) ([]byte, *time.Time, *time.Time, error) {
+	return []byte(name), nil, nil, nil
+}
+
+func (s *slowStat) Stat(name string) (fs.FileInfo, error) {
+	s.stats.Add(1)
+	<-s.release
+	return fstest.MapFS{name: {Data: []byte(name)}}.Stat(name)
+}
+
+func TestStatSourceRunsUnlocked(t *testing.T) {
+	d, err := New(StatFulfills(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &slowStat{release: make(chan struct{})}
+	d.FulfillFrom(src)
+	d.Put("cached", []byte("x"), time.Now(), nil)
+
+	done := make(chan fs.FileInfo, 2)
+	for range 2 {
+		go func() {
+			fi, _ := d.Stat("slow")
+			done <- fi
+		}()
+	}
+	for src.stats.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// a cache hit is not held up by the Stat in progress
+	if b, err := d.ReadFile("cached"); err != nil || string(b) != "x" {
+		t.Fatalf("ReadFile = %q, %v", b, err)
+	}
+	close(src.release)
+	for range 2 {
+		if fi := <-done; fi == nil || fi.Size() != int64(len("slow")) {
+			t.Errorf("Stat = %v", fi)
+		}
+	}
+}
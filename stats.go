@@ -0,0 +1,94 @@
+package gomemfs
+
+import (
+	"io/fs"
+	"slices"
+	"time"
+)
+
+// Stats describes the state of an FS at one point in time.
+type Stats struct {
+	// Keys and Bytes are the number of keys held and the total size of
+	// their content.
+	Keys  int
+	Bytes int64
+
+	// Breakers lists the Fulfillers that have failed since they last
+	// succeeded, as tracked by a CircuitBreaker, in order of index.
+	Breakers []BreakerStats
+}
+
+// Stats returns the current Stats of the FS. Nothing is fulfilled.
+func (d *FS) Stats() Stats {
+	d.mu.Lock()
+	defer d.unlock()
+	var s Stats
+	for n := range d.keys.all() {
+		if k := d.lookup(n); k != nil {
+			s.Keys++
+			s.Bytes += k.size()
+		}
+	}
+	for idx, b := range d.breakers {
+		bs := BreakerStats{Fulfiller: idx, Failures: b.failures}
+		if idx < len(d.callbacks) {
+			bs.FulfillerName = fulfillerName(d.callbacks[idx])
+		}
+		if d.now().Before(b.openUntil) {
+			bs.OpenUntil = b.openUntil
+		}
+		s.Breakers = append(s.Breakers, bs)
+	}
+	slices.SortFunc(s.Breakers, func(a, b BreakerStats) int { return a.Fulfiller - b.Fulfiller })
+	return s
+}
+
+// PrefixStats summarizes the keys beneath a directory, as returned by
+// StatPrefix.
+type PrefixStats struct {
+	Keys  int
+	Bytes int64
+
+	// Oldest and Newest are the earliest and latest modtimes of the keys,
+	// and SoonestExpiry is the earliest time one of them expires. Each is
+	// zero if there are no such keys.
+	Oldest        time.Time
+	Newest        time.Time
+	SoonestExpiry time.Time
+}
+
+// StatPrefix returns PrefixStats for the keys beneath the directory prefix,
+// or for every key if prefix is "" or ".". Only the keys beneath prefix are
+// visited, so this is cheap for a small subtree of a large FS. Nothing is
+// fulfilled.
+func (d *FS) StatPrefix(prefix string) (PrefixStats, error) {
+	var s PrefixStats
+	dir := "."
+	if prefix != "" && prefix != "." {
+		n, err := d.normalize(prefix)
+		if err != nil {
+			return s, &fs.PathError{Op: "statprefix", Path: prefix, Err: err}
+		}
+		dir = d.fold(n)
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	for name := range d.keys.under(dir) {
+		k := d.lookup(name)
+		if k == nil {
+			continue
+		}
+		s.Keys++
+		s.Bytes += k.size()
+		if s.Oldest.IsZero() || k.modtime.Before(s.Oldest) {
+			s.Oldest = k.modtime
+		}
+		if k.modtime.After(s.Newest) {
+			s.Newest = k.modtime
+		}
+		if k.expire != nil && (s.SoonestExpiry.IsZero() || k.expire.Before(s.SoonestExpiry)) {
+			s.SoonestExpiry = *k.expire
+		}
+	}
+	return s, nil
+}
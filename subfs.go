@@ -1,27 +1,171 @@
 package gomemfs
 
 import (
+	"context"
+	"io"
 	"io/fs"
+	"net/http"
 	"path"
+	"strings"
 )
 
+// A SubFS is the subtree of an FS beneath a directory, returned by FS.Sub.
+// Names passed to it must be valid according to [fs.ValidPath] and may not
+// refer to anything outside the directory, even after normalization by the
+// FS; other names are rejected with [fs.ErrInvalid]. A SubFS offers the same
+// io/fs interfaces as an FS, and methods such as ReadAt that take a name.
+// Symbolic link targets starting with a slash are resolved against the
+// directory, and links to anything outside it, like keys given to NotFoundKey
+// that are outside it, are treated as missing. Names reported by Files it
+// opens remain relative to the root of the FS.
 type SubFS struct {
-	p *FS
-	d string
+	p    *FS
+	d    string
+	root string // d, normalized
+}
+
+// within reports whether the normalized name is root or beneath it.
+func (d *FS) within(root, name string) bool {
+	if root == "." {
+		return true
+	}
+	root, name = d.fold(root), d.fold(name)
+	return name == root || strings.HasPrefix(name, root+"/")
+}
+
+// join returns the name in the parent FS of name in d.
+func (d *SubFS) join(op, name string) (string, error) {
+	if d.p.backslashes {
+		name = strings.ReplaceAll(name, "\\", "/")
+	}
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	// normalizers such as PercentDecode may turn a valid name into one that
+	// escapes d
+	n, err := d.p.normalize(name)
+	if err != nil {
+		return "", &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	if n == ".." || strings.HasPrefix(n, "../") {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return path.Join(d.d, name), nil
+}
+
+// fixErr shortens the path of an error returned by the parent FS so that it
+// is relative to d.
+func (d *SubFS) fixErr(err error) error {
+	if e, ok := err.(*fs.PathError); ok {
+		if rest, found := strings.CutPrefix(e.Path, d.d+"/"); found && d.d != "." {
+			return &fs.PathError{Op: e.Op, Path: rest, Err: e.Err}
+		}
+	}
+	return err
 }
 
 func (d *SubFS) Open(name string) (fs.File, error) {
-	return d.p.Open(path.Join(d.d, name))
+	return d.OpenContext(context.Background(), name)
+}
+
+// OpenContext is like Open, but passes ctx to FS.OpenContext.
+func (d *SubFS) OpenContext(ctx context.Context, name string) (fs.File, error) {
+	n, err := d.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := d.p.openIn(ctx, d.root, n)
+	return f, d.fixErr(err)
 }
 
 func (d *SubFS) ReadFile(name string) ([]byte, error) {
-	return d.p.ReadFile(path.Join(d.d, name))
+	n, err := d.join("readfile", name)
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.p.readFileIn(context.Background(), d.root, n)
+	return b, d.fixErr(err)
 }
 
 func (d *SubFS) Stat(name string) (fs.FileInfo, error) {
-	return d.p.Stat(path.Join(d.d, name))
+	n, err := d.join("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := d.p.statIn(d.root, n)
+	return fi, d.fixErr(err)
+}
+
+// ReadDir implements [fs.ReadDirFS].
+func (d *SubFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	n, err := d.join("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	es, err := d.p.readDirIn(d.root, n)
+	return es, d.fixErr(err)
+}
+
+// ReadLink implements [fs.ReadLinkFS].
+func (d *SubFS) ReadLink(name string) (string, error) {
+	n, err := d.join("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	t, err := d.p.ReadLink(n)
+	return t, d.fixErr(err)
+}
+
+// Lstat implements [fs.ReadLinkFS].
+func (d *SubFS) Lstat(name string) (fs.FileInfo, error) {
+	n, err := d.join("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := d.p.lstatIn(d.root, n)
+	return fi, d.fixErr(err)
+}
+
+// OpenRange is like FS.OpenRange.
+func (d *SubFS) OpenRange(name string, off, length int64) (io.ReadSeeker, error) {
+	n, err := d.join("openrange", name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := d.p.openRangeIn(d.root, n, off, length)
+	return r, d.fixErr(err)
+}
+
+// ReadAt is like FS.ReadAt.
+func (d *SubFS) ReadAt(name string, p []byte, off int64) (int, error) {
+	n, err := d.join("readat", name)
+	if err != nil {
+		return 0, err
+	}
+	c, err := d.p.readAtIn(d.root, n, p, off)
+	return c, d.fixErr(err)
+}
+
+// HTTPFileSystem returns the SubFS as an [http.FileSystem], as described for
+// FS.HTTPFileSystem.
+func (d *SubFS) HTTPFileSystem() http.FileSystem {
+	return http.FS(d)
+}
+
+// WriteTar writes every key currently held beneath the directory of d to w,
+// as described for FS.WriteTarPrefix.
+func (d *SubFS) WriteTar(w io.Writer) error {
+	return d.p.WriteTarPrefix(w, d.d)
 }
 
 func (d *SubFS) Sub(dir string) (fs.FS, error) {
-	return &SubFS{p: d.p, d: path.Join(d.d, dir)}, nil
+	n, err := d.join("sub", dir)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := d.p.sub(n)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
 }
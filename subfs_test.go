@@ -0,0 +1,122 @@
+package gomemfs
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSubFSLinks(t *testing.T) {
+	d, err := New(NotFoundKey("secret.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	d.Put("secret.txt", []byte("secret"), now, nil)
+	d.Put("site/index.html", []byte("index"), now, nil)
+	d.Put("site/secret.txt", []byte("public"), now, nil)
+	d.Symlink("/secret.txt", "site/abs")
+	d.Symlink("../secret.txt", "site/rel")
+	d.Symlink("index.html", "site/home")
+
+	sub, err := d.Sub("site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"abs": "public", "home": "index"} {
+		if b, err := fs.ReadFile(sub, name); err != nil || string(b) != want {
+			t.Errorf("ReadFile(%q) = %q, %v; want %q", name, b, err, want)
+		}
+	}
+	for _, name := range []string{"rel", "missing"} {
+		if b, err := fs.ReadFile(sub, name); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("ReadFile(%q) = %q, %v; want fs.ErrNotExist", name, b, err)
+		}
+	}
+	if _, err := fs.Stat(sub, "rel"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat(rel) = %v, want fs.ErrNotExist", err)
+	}
+
+	// the FS itself still resolves them against its root
+	if b, err := d.ReadFile("site/abs"); err != nil || string(b) != "secret" {
+		t.Errorf("ReadFile(site/abs) = %q, %v", b, err)
+	}
+	if b, err := d.ReadFile("site/missing"); err != nil || string(b) != "secret" {
+		t.Errorf("ReadFile(site/missing) = %q, %v", b, err)
+	}
+}
+
+func TestSubFSNames(t *testing.T) {
+	d, err := New(PercentDecode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("secret", []byte("secret"), time.Now(), nil)
+	d.Put("site/a", []byte("a"), time.Now(), nil)
+	sub, err := d.Sub("site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"../secret", "/a", "%2E%2E/secret", "..%2Fsecret"} {
+		if _, err := fs.ReadFile(sub, name); !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("ReadFile(%q) = %v, want fs.ErrInvalid", name, err)
+		}
+	}
+	_, err = fs.ReadFile(sub, "missing")
+	var pe *fs.PathError
+	if !errors.As(err, &pe) || pe.Path != "missing" {
+		t.Errorf("ReadFile(missing) = %v, want a PathError for missing", err)
+	}
+}
+
+func TestSubFSInterfaces(t *testing.T) {
+	d, err := New(StrictPaths(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("site/css/app.css", []byte("body{}"), time.Now(), nil)
+	d.Put("site/index.html", []byte("<html>"), time.Now(), nil)
+	d.Symlink("css/app.css", "site/style.css")
+	s, err := d.Sub("site")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub := s.(*SubFS)
+	if err := fstest.TestFS(sub, "css/app.css", "index.html", "style.css"); err != nil {
+		t.Error(err)
+	}
+	if target, err := sub.ReadLink("style.css"); err != nil || target != "css/app.css" {
+		t.Errorf("ReadLink = %q, %v", target, err)
+	}
+	if fi, err := sub.Lstat("style.css"); err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("Lstat = %v, %v", fi, err)
+	}
+	p := make([]byte, 2)
+	if n, err := sub.ReadAt("css/app.css", p, 4); err != nil || string(p[:n]) != "{}" {
+		t.Errorf("ReadAt = %q, %v", p[:n], err)
+	}
+	r, err := sub.OpenRange("index.html", 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := io.ReadAll(r); string(b) != "html" {
+		t.Errorf("OpenRange = %q", b)
+	}
+	var pe *fs.PathError
+	if _, err := sub.ReadAt("../x", p, 0); !errors.As(err, &pe) || pe.Op != "readat" {
+		t.Errorf("ReadAt(../x) = %v, want a readat PathError", err)
+	}
+	if _, err := sub.OpenRange("../x", 0, 1); !errors.As(err, &pe) || pe.Op != "openrange" {
+		t.Errorf("OpenRange(../x) = %v, want an openrange PathError", err)
+	}
+	nested, err := sub.Sub("css")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := fs.ReadFile(nested, "app.css"); err != nil || string(b) != "body{}" {
+		t.Errorf("nested ReadFile = %q, %v", b, err)
+	}
+}
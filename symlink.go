@@ -0,0 +1,138 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// maxLinks is the number of symbolic links followed while resolving a name
+// before giving up.
+const maxLinks = 40
+
+var errLinkLoop = errors.New("too many levels of symbolic links")
+
+// Symlink creates name as a symbolic link to target, replacing any existing
+// key called name. Opening name opens target instead, without copying its
+// content, so eg "latest/report.pdf" can always point at the newest report.
+// As in a POSIX filesystem, a relative target is resolved against the
+// directory containing name, and a target starting with a slash is resolved
+// against the root of the FS: the link "latest/report.pdf" should have the
+// target "/reports/2026-10.pdf" or "../reports/2026-10.pdf". The target does
+// not need to exist. Only the last element of a name is resolved as a link.
+func (d *FS) Symlink(target, name string) error {
+	if target == "" {
+		return &fs.PathError{Op: "symlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return d.putKey(&key{
+		name:    name,
+		link:    path.Clean(target),
+		modtime: d.now(),
+	})
+}
+
+// ReadLink implements [fs.ReadLinkFS]. It returns the target of the symbolic
+// link name as it was given to Symlink.
+func (d *FS) ReadLink(name string) (string, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	k := d.lookup(n)
+	if k == nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrNotExist}
+	}
+	if k.link == "" {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return k.link, nil
+}
+
+// Lstat implements [fs.ReadLinkFS]. It is like Stat, but if name is a
+// symbolic link, the returned FileInfo describes the link itself.
+func (d *FS) Lstat(name string) (fs.FileInfo, error) {
+	return d.lstatIn(".", name)
+}
+
+// lstatIn is Lstat, resolving links within root.
+func (d *FS) lstatIn(root, name string) (fs.FileInfo, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	k := d.lookup(n)
+	d.unlock()
+	if k != nil && k.link != "" {
+		return k.fileStat(), nil
+	}
+	return d.statIn(root, name)
+}
+
+// target returns the normalized name of the key a link points to, resolving a
+// target starting with a slash against root, as for a SubFS. A target outside
+// root is reported as missing.
+func (k *key) target(root string) (string, error) {
+	// only the target as given to Symlink is rewritten, not the name of the
+	// directory containing k, which already was
+	t, err := k.fs.rewrite(k.link)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(t, "/") {
+		t = path.Join(root, strings.TrimPrefix(path.Clean(t), "/"))
+	} else {
+		t = path.Join(path.Dir(k.name), t)
+	}
+	if t, err = k.fs.clean(t); err != nil {
+		return "", err
+	}
+	if !k.fs.within(root, t) {
+		return "", fs.ErrNotExist
+	}
+	return t, nil
+}
+
+// relTarget returns the target of a link relative to the directory
+// containing it, as expected by archive formats and other filesystems.
+func (k *key) relTarget() string {
+	if !strings.HasPrefix(k.link, "/") {
+		return k.link
+	}
+	from := strings.Split(path.Dir(k.name), "/")
+	to := strings.Split(strings.TrimPrefix(k.link, "/"), "/")
+	if from[0] == "." {
+		from = nil
+	}
+	i := 0
+	for i < len(from) && i < len(to)-1 && from[i] == to[i] {
+		i++
+	}
+	return strings.Repeat("../", len(from)-i) + strings.Join(to[i:], "/")
+}
+
+func (d *FS) follow(name string) (string, error) {
+	// must be called with fs.mu Locked
+	return d.followIn(".", name)
+}
+
+// followIn is like follow, but resolves links as if root was the root of the
+// FS.
+func (d *FS) followIn(root, name string) (string, error) {
+	// must be called with fs.mu Locked
+	for range maxLinks {
+		k := d.lookup(name)
+		if k == nil || k.link == "" {
+			return name, nil
+		}
+		t, err := k.target(root)
+		if err != nil {
+			return "", err
+		}
+		name = t
+	}
+	return "", errLinkLoop
+}
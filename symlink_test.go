@@ -0,0 +1,52 @@
+package gomemfs
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestSymlink(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("reports/2026-10.pdf", []byte("october"), time.Now(), nil)
+	d.Symlink("/reports/2026-10.pdf", "latest/abs.pdf")
+	d.Symlink("../reports/2026-10.pdf", "latest/rel.pdf")
+	d.Symlink("rel.pdf", "latest/chain.pdf")
+	d.Symlink("loop-b", "loop-a")
+	d.Symlink("loop-a", "loop-b")
+	d.Symlink("nowhere", "dangling")
+
+	for _, name := range []string{"latest/abs.pdf", "latest/rel.pdf", "latest/chain.pdf"} {
+		if b, err := d.ReadFile(name); err != nil || string(b) != "october" {
+			t.Errorf("ReadFile(%q) = %q, %v", name, b, err)
+		}
+	}
+	if target, err := d.ReadLink("latest/rel.pdf"); err != nil || target != "../reports/2026-10.pdf" {
+		t.Errorf("ReadLink = %q, %v", target, err)
+	}
+	if _, err := d.ReadLink("reports/2026-10.pdf"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("ReadLink of a file = %v, want fs.ErrInvalid", err)
+	}
+	if fi, err := d.Lstat("latest/abs.pdf"); err != nil || fi.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("Lstat = %v, %v; want a symlink", fi, err)
+	}
+	if fi, err := d.Stat("latest/abs.pdf"); err != nil || fi.Mode()&fs.ModeSymlink != 0 || fi.Size() != 7 {
+		t.Errorf("Stat = %v, %v; want the target", fi, err)
+	}
+	if _, err := d.ReadFile("loop-a"); !errors.Is(err, errLinkLoop) {
+		t.Errorf("ReadFile(loop-a) = %v, want errLinkLoop", err)
+	}
+	if _, err := d.ReadFile("dangling"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("ReadFile(dangling) = %v, want fs.ErrNotExist", err)
+	}
+	if err := d.Symlink("", "empty"); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("Symlink with no target = %v", err)
+	}
+	if rel := d.keys.get("latest/abs.pdf").relTarget(); rel != "../reports/2026-10.pdf" {
+		t.Errorf("relTarget = %q", rel)
+	}
+}
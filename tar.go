@@ -0,0 +1,68 @@
+package gomemfs
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"strings"
+)
+
+// WriteTar writes every key currently held by the FS to w as a tar archive,
+// with the key's name, size, mode, modtime, owner and PAX records. Symbolic
+// links are written as tar symlinks with a target relative to the link. Keys
+// are written in order of name and nothing is fulfilled. The archive is
+// finished but w is not closed.
+func (d *FS) WriteTar(w io.Writer) error {
+	return d.WriteTarPrefix(w, ".")
+}
+
+// WriteTarPrefix is like WriteTar, but only writes keys beneath the directory
+// prefix, named relative to it.
+func (d *FS) WriteTarPrefix(w io.Writer, prefix string) error {
+	p, err := d.normalize(prefix)
+	if err != nil {
+		return &fs.PathError{Op: "writetarprefix", Path: prefix, Err: err}
+	}
+	if p == "." {
+		p = ""
+	} else {
+		p += "/"
+	}
+
+	tw := tar.NewWriter(w)
+	for _, k := range d.snapshot(p) {
+		b, err := k.content()
+		if err != nil {
+			return fmt.Errorf("cannot write tar content: %w", err)
+		}
+		hdr := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     strings.TrimPrefix(k.name, p),
+			Size:     k.size(),
+			Mode:     int64(k.exportMode().Perm()),
+			ModTime:  k.modtime,
+			Uid:      k.owner.UID,
+			Gid:      k.owner.GID,
+			Uname:    k.owner.Uname,
+			Gname:    k.owner.Gname,
+		}
+		if len(k.pax) > 0 {
+			hdr.PAXRecords = maps.Clone(k.pax)
+			hdr.Format = tar.FormatPAX
+		}
+		if k.link != "" {
+			hdr.Typeflag = tar.TypeSymlink
+			hdr.Linkname = k.relTarget()
+			hdr.Mode = 0o777
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("cannot write tar header for %q: %w", k.name, err)
+		}
+		if _, err := tw.Write(b); err != nil {
+			return fmt.Errorf("cannot write tar content for %q: %w", k.name, err)
+		}
+	}
+	return tw.Close()
+}
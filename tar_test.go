@@ -0,0 +1,89 @@
+package gomemfs
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+// readTar returns the headers and contents of the entries in a tar archive.
+func readTar(t *testing.T, b []byte) ([]*tar.Header, []string) {
+	t.Helper()
+	var hdrs []*tar.Header
+	var contents []string
+	tr := tar.NewReader(bytes.NewReader(b))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return hdrs, contents
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, _ := io.ReadAll(tr)
+		hdrs, contents = append(hdrs, hdr), append(contents, string(c))
+	}
+}
+
+func TestWriteTar(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Put("etc/conf", []byte("conf"), mt, nil,
+		Mode(0o600),
+		Owner{UID: 1000, Gname: "staff"},
+		PAXRecords{"SCHILY.xattr.user.a": "1"},
+		PAXRecords{"SCHILY.xattr.user.b": "2"},
+	)
+	d.Put("etc/plain", []byte("plain"), mt, nil)
+	d.Symlink("/etc/conf", "etc/link")
+	d.Put("other", []byte("other"), mt, nil)
+
+	var buf bytes.Buffer
+	if err := d.WriteTar(&buf); err != nil {
+		t.Fatal(err)
+	}
+	hdrs, contents := readTar(t, buf.Bytes())
+	if len(hdrs) != 4 {
+		t.Fatalf("%d entries, want 4", len(hdrs))
+	}
+	conf := hdrs[0]
+	if conf.Name != "etc/conf" || contents[0] != "conf" || conf.Mode != 0o600 || conf.Uid != 1000 || conf.Gname != "staff" || !conf.ModTime.Equal(mt) {
+		t.Errorf("etc/conf = %+v", conf)
+	}
+	if conf.PAXRecords["SCHILY.xattr.user.a"] != "1" || conf.PAXRecords["SCHILY.xattr.user.b"] != "2" {
+		t.Errorf("PAXRecords = %v", conf.PAXRecords)
+	}
+	if link := hdrs[1]; link.Typeflag != tar.TypeSymlink || link.Linkname != "conf" {
+		t.Errorf("etc/link = %+v", link)
+	}
+	if hdrs[2].Mode != 0o644 {
+		t.Errorf("etc/plain mode = %o, want 644", hdrs[2].Mode)
+	}
+
+	buf.Reset()
+	if err := d.WriteTarPrefix(&buf, "etc"); err != nil {
+		t.Fatal(err)
+	}
+	hdrs, _ = readTar(t, buf.Bytes())
+	if len(hdrs) != 3 || hdrs[0].Name != "conf" {
+		t.Errorf("WriteTarPrefix wrote %d entries, first %q", len(hdrs), hdrs[0].Name)
+	}
+}
+
+func TestWriteTarPrefixInvalid(t *testing.T) {
+	d, err := New(StrictPaths(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pe *fs.PathError
+	if err := d.WriteTarPrefix(io.Discard, "/bad"); !errors.As(err, &pe) || pe.Op != "writetarprefix" || pe.Path != "/bad" {
+		t.Errorf("WriteTarPrefix = %v, want a *fs.PathError", err)
+	}
+}
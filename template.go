@@ -0,0 +1,84 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"path"
+	texttemplate "text/template"
+	"time"
+)
+
+// A DataFunc provides the data a template is executed with for path. If it
+// returns an error wrapping [fs.ErrNotExist], the path falls through to other
+// Fulfillers.
+type DataFunc func(path string) (any, error)
+
+// HTMLTemplate returns a Fulfiller that renders the [html/template] in t named
+// by the requested path, executed with the result of data for that path. Any
+// templates in t matching the shared patterns (see [htmltemplate.ParseFS]),
+// such as layouts and partials, are parsed along with it. Paths for which t
+// has no file fall through to other Fulfillers. If ttl is not nil, the
+// rendered content will be set to expire at time.Now().Add(ttl).
+func HTMLTemplate(t fs.FS, data DataFunc, ttl *time.Duration, shared ...string) Fulfiller {
+	return renderTemplate(t, data, ttl, func(name, src string) (executor, error) {
+		tmpl, err := htmltemplate.New(name).Parse(src)
+		if err == nil && len(shared) > 0 {
+			tmpl, err = tmpl.ParseFS(t, shared...)
+		}
+		return tmpl, err
+	})
+}
+
+// TextTemplate is like HTMLTemplate, but uses [text/template], which does not
+// escape its output.
+func TextTemplate(t fs.FS, data DataFunc, ttl *time.Duration, shared ...string) Fulfiller {
+	return renderTemplate(t, data, ttl, func(name, src string) (executor, error) {
+		tmpl, err := texttemplate.New(name).Parse(src)
+		if err == nil && len(shared) > 0 {
+			tmpl, err = tmpl.ParseFS(t, shared...)
+		}
+		return tmpl, err
+	})
+}
+
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+func renderTemplate(t fs.FS, data DataFunc, ttl *time.Duration, parse func(name, src string) (executor, error)) Fulfiller {
+	return func(p string) ([]byte, *time.Time, *time.Time, error) {
+		src, err := fs.ReadFile(t, p)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot read template %q in %T: %w", p, t, err)
+		}
+		tmpl, err := parse(path.Base(p), string(src))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot parse template %q: %w", p, err)
+		}
+		v, err := data(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, nil, nil
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot get data for template %q: %w", p, err)
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, v); err != nil {
+			return nil, nil, nil, fmt.Errorf("cannot execute template %q: %w", p, err)
+		}
+
+		if ttl != nil {
+			expire := time.Now().Add(*ttl)
+			return buf.Bytes(), nil, &expire, nil
+		}
+		return buf.Bytes(), nil, nil, nil
+	}
+}
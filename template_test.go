@@ -0,0 +1,40 @@
+package gomemfs
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTemplates(t *testing.T) {
+	tmpls := fstest.MapFS{
+		"layout.tmpl":    {Data: []byte(`{{define "title"}}<b>{{.}}</b>{{end}}`)},
+		"users/42.html":  {Data: []byte(`{{template "title" .}}`)},
+		"users/404.html": {Data: []byte(`{{.}}`)},
+	}
+	data := func(p string) (any, error) {
+		if p == "users/404.html" {
+			return nil, fmt.Errorf("no user: %w", fs.ErrNotExist)
+		}
+		return "<Ann>", nil
+	}
+
+	b, _, _, err := HTMLTemplate(tmpls, data, nil, "*.tmpl")("users/42.html")
+	if err != nil || string(b) != "<b>&lt;Ann&gt;</b>" {
+		t.Errorf("HTMLTemplate = %q, %v", b, err)
+	}
+	b, _, _, err = TextTemplate(tmpls, data, nil, "*.tmpl")("users/42.html")
+	if err != nil || string(b) != "<b><Ann></b>" {
+		t.Errorf("TextTemplate = %q, %v", b, err)
+	}
+	for _, p := range []string{"users/404.html", "missing.html"} {
+		if b, _, _, err := HTMLTemplate(tmpls, data, nil)(p); b != nil || err != nil {
+			t.Errorf("%s = %q, %v; want a miss", p, b, err)
+		}
+	}
+	bad := fstest.MapFS{"bad.html": {Data: []byte(`{{`)}}
+	if _, _, _, err := HTMLTemplate(bad, data, nil)("bad.html"); err == nil {
+		t.Error("HTMLTemplate parsed an invalid template")
+	}
+}
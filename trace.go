@@ -0,0 +1,89 @@
+package gomemfs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+// A Tracer starts spans around work done by an FS. It is intentionally small
+// so that it can be adapted to OpenTelemetry (or any other tracing library)
+// without this package depending on one. The context passed to Start is the
+// one given to eg FS.OpenContext, so spans can be parented to the caller.
+type Tracer interface {
+	Start(ctx context.Context, op string, path string) (context.Context, Span)
+}
+
+// A Span is ended exactly once with the outcome of the traced operation.
+type Span interface {
+	End(TraceResult)
+}
+
+// TraceResult describes the outcome of a traced operation.
+type TraceResult struct {
+	// Fulfiller is the index (in the order passed to FulfillWith) of the
+	// Fulfiller that produced content or returned an error, or -1 if none did.
+	Fulfiller int
+
+	// FulfillerName is the function name of that Fulfiller, or the type of
+	// the Source, if known.
+	FulfillerName string
+
+	// Bytes is the length of the content produced.
+	Bytes int
+
+	// Duration is the time spent on the operation.
+	Duration time.Duration
+
+	// Err is the error returned to the caller, if any.
+	Err error
+}
+
+// Span operation names passed to Tracer.Start.
+const (
+	TraceFulfill = "gomemfs.fulfill"
+	TraceMiss    = "gomemfs.miss"
+)
+
+// WithTracer returns an FSOption that starts a span with t around every
+// fulfillment. If misses is true, a span is also started for cache misses
+// that do not lead to fulfillment, such as FS.Stat without StatFulfills.
+func WithTracer(t Tracer, misses bool) FSOption {
+	return tracerOption{t: t, misses: misses}
+}
+
+type tracerOption struct {
+	t      Tracer
+	misses bool
+}
+
+func (fso tracerOption) applyTo(fs *FS) error {
+	fs.tracer = fso.t
+	fs.traceMisses = fso.misses
+	return nil
+}
+
+func (d *FS) traceMiss(ctx context.Context, name string) {
+	// must be called with fs.mu Locked
+	if d.tracer == nil || !d.traceMisses {
+		return
+	}
+	_, s := d.tracer.Start(ctx, TraceMiss, name)
+	s.End(TraceResult{Fulfiller: -1})
+}
+
+func fulfillerName(s Source) string {
+	if t, ok := s.(*ttlSource); ok {
+		s = t.f
+	}
+	f, ok := s.(Fulfiller)
+	if !ok {
+		return fmt.Sprintf("%T", s)
+	}
+	if fn := runtime.FuncForPC(reflect.ValueOf(f).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package gomemfs
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type spanKey struct{}
+
+type testTracer struct{ ended []TraceResult }
+
+func (tr *testTracer) Start(ctx context.Context, op, path string) (context.Context, Span) {
+	return context.WithValue(ctx, spanKey{}, op+" "+path), &testSpan{tr}
+}
+
+type testSpan struct{ tr *testTracer }
+
+func (s *testSpan) End(r TraceResult) { s.tr.ended = append(s.tr.ended, r) }
+
+func TestTraceFulfillContext(t *testing.T) {
+	tr := &testTracer{}
+	d, err := New(WithTracer(tr, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parent any
+	d.FulfillFrom(ContextFulfiller(func(ctx context.Context, p string) ([]byte, *time.Time, *time.Time, error) {
+		parent = ctx.Value(spanKey{})
+		return []byte("x"), nil, nil, nil
+	}))
+	if _, err := d.ReadFile("a"); err != nil {
+		t.Fatal(err)
+	}
+	if parent != TraceFulfill+" a" {
+		t.Errorf("Source ran beneath %v, want the fulfill span", parent)
+	}
+	if len(tr.ended) != 1 || tr.ended[0].Fulfiller != 0 || tr.ended[0].Bytes != 1 {
+		t.Errorf("ended spans = %+v", tr.ended)
+	}
+}
@@ -0,0 +1,34 @@
+package gomemfs
+
+import (
+	"fmt"
+	"time"
+)
+
+// A Stage transforms the content produced for path, eg by minifying or
+// rewriting it. Returning nil content causes the path to fall through to
+// other Fulfillers.
+type Stage func(path string, content []byte) ([]byte, error)
+
+// Transform wraps f so that the content it produces is passed through each of
+// stages in order before being cached. The modtime and expiry from f are kept.
+// If a stage returns an error, it is returned from the Fulfiller like any
+// other fulfillment error.
+func Transform(f Fulfiller, stages ...Stage) Fulfiller {
+	return func(path string) ([]byte, *time.Time, *time.Time, error) {
+		content, modtime, expire, err := f(path)
+		if err != nil || content == nil {
+			return content, modtime, expire, err
+		}
+		for i, s := range stages {
+			content, err = s(path, content)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("cannot transform %q in stage %d: %w", path, i, err)
+			}
+			if content == nil {
+				return nil, nil, nil, nil
+			}
+		}
+		return content, modtime, expire, nil
+	}
+}
@@ -0,0 +1,43 @@
+package gomemfs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransform(t *testing.T) {
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := func(p string) ([]byte, *time.Time, *time.Time, error) {
+		if p == "missing" {
+			return nil, nil, nil, nil
+		}
+		return []byte(" Hello "), &mt, nil, nil
+	}
+	errBad := errors.New("bad")
+	tf := Transform(f,
+		func(p string, b []byte) ([]byte, error) { return bytes.TrimSpace(b), nil },
+		func(p string, b []byte) ([]byte, error) {
+			switch p {
+			case "skip":
+				return nil, nil
+			case "bad":
+				return nil, errBad
+			}
+			return bytes.ToUpper(b), nil
+		},
+	)
+	if b, m, _, err := tf("a"); err != nil || string(b) != "HELLO" || m == nil || !m.Equal(mt) {
+		t.Errorf("a = %q, %v, %v", b, m, err)
+	}
+	if b, _, _, err := tf("skip"); b != nil || err != nil {
+		t.Errorf("skip = %q, %v; want a miss", b, err)
+	}
+	if _, _, _, err := tf("bad"); !errors.Is(err, errBad) {
+		t.Errorf("bad = %v", err)
+	}
+	if b, _, _, err := tf("missing"); b != nil || err != nil {
+		t.Errorf("missing = %q, %v; want a miss", b, err)
+	}
+}
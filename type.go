@@ -1,9 +1,86 @@
 package gomemfs
 
 import (
+	"context"
+	"io/fs"
 	"time"
 )
 
 // A Fulfiller is a callback that receives a normalized path string and tries
-// to obtain the byte contents for that path.
+// to obtain the byte contents for that path. It returns nil content if it has
+// nothing for the path. If expire points to the zero time, or is nil and the
+// FS has no DefaultTTL, the content is served once but not cached; if it
+// points to Forever, the content is cached until it is removed or evicted. An
+// error wrapping [fs.ErrNotExist] is treated like nil content, so that the
+// next Fulfiller is tried, and is recorded in the resulting MissError; any
+// other error is returned to the caller. Fulfillers run without the FS locked, and
+// may be called concurrently for different paths; for a single path there is
+// at most one call at a time, whose result other callers wait for.
 type Fulfiller func(path string) (content []byte, modtime *time.Time, expire *time.Time, err error)
+
+// Forever is an expire time for a Fulfiller to return for content that is to be
+// cached without expiring, as a Fulfiller's nil expire means the content is not
+// cached at all unless DefaultTTL is set. Keys fulfilled with it have a nil
+// expire, as if put with one.
+var Forever = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+// Fulfill calls f, so that every Fulfiller is also a Source.
+func (f Fulfiller) Fulfill(path string) ([]byte, *time.Time, *time.Time, error) {
+	return f(path)
+}
+
+// A Source is the interface form of a Fulfiller, for implementations that have
+// more to offer than content. A Source may implement optional interfaces such
+// as StatSource, which the FS uses when available. Sources are added to an FS
+// with FulfillFrom.
+type Source interface {
+	Fulfill(path string) (content []byte, modtime *time.Time, expire *time.Time, err error)
+}
+
+// A StatSource is a Source that can describe a path without producing its
+// content. When StatFulfills is set, FS.Stat uses it instead of fulfilling
+// the path, so the content is only fetched when the path is opened. Stat
+// returns a nil FileInfo and a nil error if the Source has nothing for path.
+type StatSource interface {
+	Source
+	Stat(path string) (fs.FileInfo, error)
+}
+
+// A ModeSource is a Source that reports the permission bits of the content it
+// fulfills. Mode is called after Fulfill returns content for path, and its
+// result is stored on the key as if given to Put with Mode.
+type ModeSource interface {
+	Source
+	Mode(path string) fs.FileMode
+}
+
+// A ContextSource is a Source that accepts the context passed to eg
+// FS.OpenContext, so that it can honor cancellation, attach trace spans or
+// read values set with WithRequestValues. The FS calls FulfillContext instead
+// of Fulfill when a Source implements it.
+type ContextSource interface {
+	Source
+	FulfillContext(ctx context.Context, path string) (content []byte, modtime *time.Time, expire *time.Time, err error)
+}
+
+// A ContextFulfiller is a Fulfiller that receives a context. It is a
+// ContextSource, to be added to an FS with FulfillFrom.
+type ContextFulfiller func(ctx context.Context, path string) (content []byte, modtime *time.Time, expire *time.Time, err error)
+
+// Fulfill calls f with context.Background.
+func (f ContextFulfiller) Fulfill(path string) ([]byte, *time.Time, *time.Time, error) {
+	return f(context.Background(), path)
+}
+
+// FulfillContext calls f.
+func (f ContextFulfiller) FulfillContext(ctx context.Context, path string) ([]byte, *time.Time, *time.Time, error) {
+	return f(ctx, path)
+}
+
+// fulfillContext fulfills path from s, passing ctx if s is a ContextSource.
+func fulfillContext(ctx context.Context, s Source, path string) ([]byte, *time.Time, *time.Time, error) {
+	if cs, ok := s.(ContextSource); ok {
+		return cs.FulfillContext(ctx, path)
+	}
+	return s.Fulfill(path)
+}
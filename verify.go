@@ -0,0 +1,74 @@
+package gomemfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// VerifyContent, if true, causes the content of every key to be hashed when
+// it is stored and hashed again whenever it is read, eg by Open or ReadFile,
+// which fails with a *CorruptError if the content has changed since. This
+// guards against a caller modifying a buffer after passing it to Put. Keys
+// held encrypted with EncryptContent are verified by decryption instead.
+type VerifyContent bool
+
+func (fso VerifyContent) applyTo(fs *FS) error {
+	fs.verify = bool(fso)
+	return nil
+}
+
+// A CorruptError is returned, usually wrapped in an [fs.PathError], when the
+// content of a key no longer matches what was stored, as detected by
+// VerifyContent or EncryptContent.
+type CorruptError struct {
+	Name string
+
+	// Err is the error from decrypting the content, if any.
+	Err error
+}
+
+func (e *CorruptError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("content of %q is corrupt: %v", e.Name, e.Err)
+	}
+	return fmt.Sprintf("content of %q is corrupt", e.Name)
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
+
+// content returns the content of k, decrypting it if needed, and checks it
+// for VerifyContent. The result must not be modified, as it may be the
+// buffer held by k.
+func (k *key) content() ([]byte, error) {
+	b, err := k.plain()
+	if err != nil {
+		return nil, err
+	}
+	if k.fs != nil && k.fs.verify && k.aead == nil && sha256.Sum256(b) != k.sum() {
+		return nil, &CorruptError{Name: k.name}
+	}
+	if k.wipe && k.aead == nil {
+		// the buffer is wiped once the key is retired, even while b is used
+		b = bytes.Clone(b)
+	}
+	return b, nil
+}
+
+// Verify checks the content of every key held by the FS, as VerifyContent
+// does when a key is read, and returns the *CorruptErrors found, joined with
+// [errors.Join]. Corrupt keys are not removed. Calling it periodically
+// detects corruption of keys that are rarely read. Without VerifyContent,
+// only keys held encrypted can be checked.
+func (d *FS) Verify() error {
+	var errs []error
+	for _, k := range d.snapshot("") {
+		if _, err := k.content(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,39 @@
+package gomemfs
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyContent(t *testing.T) {
+	d, err := New(VerifyContent(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte("hello")
+	d.Put("a", buf, time.Now(), nil)
+	d.Put("b", []byte("world"), time.Now(), nil)
+	if err := d.Verify(); err != nil {
+		t.Fatalf("Verify = %v", err)
+	}
+
+	// the buffer is shared with the key, so modifying it corrupts a
+	buf[0] = 'j'
+	var ce *CorruptError
+	if _, err := d.ReadFile("a"); !errors.As(err, &ce) || ce.Name != "a" {
+		t.Errorf("ReadFile = %v, want a CorruptError for a", err)
+	}
+	if _, err := d.Open("a"); !errors.As(err, &ce) {
+		t.Errorf("Open = %v, want a CorruptError", err)
+	}
+	if b, err := d.ReadFile("b"); err != nil || string(b) != "world" {
+		t.Errorf("ReadFile(b) = %q, %v", b, err)
+	}
+	if err := d.Verify(); !errors.As(err, &ce) || ce.Name != "a" {
+		t.Errorf("Verify = %v, want a CorruptError for a", err)
+	}
+	if !d.Exists("a") {
+		t.Error("Verify removed the corrupt key")
+	}
+}
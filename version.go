@@ -0,0 +1,155 @@
+package gomemfs
+
+import (
+	"context"
+	"io/fs"
+	"time"
+)
+
+// KeepVersions, if greater than zero, causes an FS to keep up to that many
+// previous versions of each key when it is replaced by Put, ReplaceAll or
+// Merge, fulfilled again after expiring, or removed. Previous versions are
+// listed by Versions and opened with OpenVersion, eg to see what an asset
+// looked like before the last deploy. They count towards neither MaxKeys nor
+// quotas, but their content stays in memory.
+type KeepVersions int
+
+func (fso KeepVersions) applyTo(fs *FS) error {
+	fs.keepVersions = int(fso)
+	if fso <= 0 {
+		fs.history = nil
+	}
+	return nil
+}
+
+// A Version describes a previous version of a key.
+type Version struct {
+	Size    int64
+	ModTime time.Time
+
+	// Stored is the time the version was put or fulfilled, and Replaced the
+	// time it stopped being current.
+	Stored   time.Time
+	Replaced time.Time
+}
+
+// retire records k, which is no longer current, as the latest previous
+// version of its key, or discards it if versions are not kept.
+func (d *FS) retire(k *key) {
+	// must be called with fs.mu Locked
+	if k == nil || k.info != nil {
+		return
+	}
+	if d.keepVersions <= 0 {
+		d.discard(k)
+		return
+	}
+	if k.replaced.IsZero() {
+		k.replaced = d.now()
+	}
+	if d.history == nil {
+		d.history = make(map[string][]*key)
+	}
+	f := d.fold(k.name)
+	h := append([]*key{k}, d.history[f]...)
+	if len(h) > d.keepVersions {
+		for _, old := range h[d.keepVersions:] {
+			d.discard(old)
+		}
+		h = h[:d.keepVersions]
+	}
+	d.history[f] = h
+}
+
+// Versions returns the previous versions of key name, newest first. Version n
+// (counting from 1) can be opened with OpenVersion.
+func (d *FS) Versions(name string) ([]Version, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "versions", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	if err = d.authorized(context.Background(), n); err != nil {
+		return nil, &fs.PathError{Op: "versions", Path: name, Err: err}
+	}
+	h := d.history[d.fold(n)]
+	vs := make([]Version, len(h))
+	for i, k := range h {
+		vs[i] = Version{
+			Size:     k.size(),
+			ModTime:  k.modtime,
+			Stored:   k.stored,
+			Replaced: k.replaced,
+		}
+	}
+	return vs, nil
+}
+
+// OpenVersion opens previous version n of key name, where 1 is the version
+// replaced most recently, as listed by Versions. Version 0 is the current
+// version, which is fulfilled if needed as by Open.
+func (d *FS) OpenVersion(name string, n int) (*File, error) {
+	if n == 0 {
+		f, err := d.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		if mf, ok := f.(*File); ok {
+			return mf, nil
+		}
+		f.Close()
+		return nil, &fs.PathError{Op: "openversion", Path: name, Err: errIsDir}
+	}
+	nn, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "openversion", Path: name, Err: err}
+	}
+	d.mu.Lock()
+	defer d.unlock()
+	if err = d.authorized(context.Background(), nn); err != nil {
+		return nil, &fs.PathError{Op: "openversion", Path: name, Err: err}
+	}
+	h := d.history[d.fold(nn)]
+	if n < 0 || n > len(h) {
+		return nil, &fs.PathError{Op: "openversion", Path: name, Err: fs.ErrNotExist}
+	}
+	f, err := h[n-1].open()
+	if err != nil {
+		return nil, &fs.PathError{Op: "openversion", Path: name, Err: err}
+	}
+	return f, nil
+}
+
+// At returns a read-only view of the FS as it was at time t, in which each
+// key resolves to the version that was current at t, as kept by
+// KeepVersions, and keys that had expired at t are missing. Nothing is ever
+// fulfilled in the view. The view is a sealed FS holding a snapshot of the
+// keys taken when At is called; it does not follow later changes.
+func (d *FS) At(t time.Time) *FS {
+	d.mu.Lock()
+	defer d.unlock()
+	v := &FS{
+		options: d.options,
+		sealed:  true,
+	}
+	v.clock = func() time.Time { return t }
+	v.keepVersions = 0
+	for f, k := range d.keys.all() {
+		if k != nil && !k.stored.After(t) {
+			v.keys.set(f, k.clone(v))
+		}
+	}
+	for f, h := range d.history {
+		if _, ok := v.keys.lookup(f); ok {
+			continue
+		}
+		for _, k := range h {
+			if !k.stored.After(t) && k.replaced.After(t) {
+				v.keys.set(f, k.clone(v))
+				break
+			}
+		}
+	}
+	return v
+}
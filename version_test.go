@@ -0,0 +1,112 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestKeepVersions(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(KeepVersions(2), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"v1", "v2!", "v3!!"} {
+		d.Put("app.js", []byte(s), now, nil)
+		now = now.Add(time.Minute)
+	}
+	vs, err := d.Versions("app.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 2 || vs[0].Size != 3 || vs[1].Size != 2 || !vs[0].Replaced.Equal(vs[0].Stored.Add(time.Minute)) {
+		t.Errorf("Versions = %+v", vs)
+	}
+	read := func(n int) string {
+		t.Helper()
+		f, err := d.OpenVersion("app.js", n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		b, _ := io.ReadAll(f)
+		return string(b)
+	}
+	if got := read(0); got != "v3!!" {
+		t.Errorf("version 0 = %q", got)
+	}
+	if got := read(1); got != "v2!" {
+		t.Errorf("version 1 = %q", got)
+	}
+	if _, err := d.OpenVersion("app.js", 3); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("OpenVersion(3) = %v, want fs.ErrNotExist", err)
+	}
+
+	d.Expire("app.js")
+	if vs, _ := d.Versions("app.js"); len(vs) != 2 || read(1) != "v3!!" {
+		t.Errorf("Versions after Expire = %+v", vs)
+	}
+	d.Set(KeepVersions(0))
+	if vs, _ := d.Versions("app.js"); len(vs) != 0 {
+		t.Errorf("Versions without KeepVersions = %+v", vs)
+	}
+}
+
+func TestAt(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d, err := New(KeepVersions(4), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := now
+	d.Put("app.js", []byte("old"), now, nil)
+	expire := now.Add(90 * time.Second)
+	d.Put("banner", []byte("sale"), now, &expire)
+	now = now.Add(time.Minute)
+	deploy := now
+	d.Put("app.js", []byte("new"), now, nil)
+	d.Put("added", []byte("x"), now, nil)
+	now = now.Add(time.Hour)
+
+	v := d.At(start)
+	if b, err := v.ReadFile("app.js"); err != nil || string(b) != "old" {
+		t.Errorf("ReadFile at start = %q, %v", b, err)
+	}
+	if v.Exists("added") {
+		t.Error("key stored later is in the view")
+	}
+	if !v.Sealed() {
+		t.Error("view is not sealed")
+	}
+	v = d.At(deploy)
+	if b, err := v.ReadFile("app.js"); err != nil || string(b) != "new" {
+		t.Errorf("ReadFile at deploy = %q, %v", b, err)
+	}
+	if !v.Exists("banner") || d.At(now).Exists("banner") {
+		t.Error("expiry not judged at the time of the view")
+	}
+}
+
+func TestVersionsAuthorized(t *testing.T) {
+	d, err := New(KeepVersions(2), Authorizer(func(ctx context.Context, name string) error {
+		if name == "secret" {
+			return errors.New("denied")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("secret", []byte("v1"), time.Now(), nil)
+	d.Put("secret", []byte("v2"), time.Now(), nil)
+	if _, err := d.Versions("secret"); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Versions = %v, want fs.ErrPermission", err)
+	}
+	if _, err := d.OpenVersion("secret", 1); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("OpenVersion = %v, want fs.ErrPermission", err)
+	}
+}
@@ -0,0 +1,41 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+)
+
+// WaitOpen is like OpenContext, but if name does not exist it blocks until a
+// key of that name is put or fulfilled (eg by another goroutine) and then
+// opens it, or until ctx is done, in which case the error wraps ctx.Err().
+// This allows a consumer to wait on keys written by a producer.
+func (d *FS) WaitOpen(ctx context.Context, name string) (fs.File, error) {
+	n, err := d.normalize(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "waitopen", Path: name, Err: err}
+	}
+	n = d.fold(n)
+
+	// subscribe before the first attempt, so that a key put in between is
+	// not missed; a buffer of one is enough, as any event for the key means
+	// it is worth trying again
+	s := &subscription{
+		c:     make(chan Event, 1),
+		match: func(ev string) bool { return d.fold(ev) == n },
+	}
+	cancel := d.subscribe(s)
+	defer cancel()
+
+	for {
+		f, err := d.OpenContext(ctx, name)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return f, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, &fs.PathError{Op: "waitopen", Path: name, Err: ctx.Err()}
+		case <-s.c:
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package gomemfs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWaitOpen(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(chan string, 1)
+	go func() {
+		f, err := d.WaitOpen(context.Background(), "job/result")
+		if err != nil {
+			got <- err.Error()
+			return
+		}
+		defer f.Close()
+		b, _ := io.ReadAll(f)
+		got <- string(b)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	d.Put("job/other", []byte("no"), time.Now(), nil)
+	d.Put("job/result", []byte("done"), time.Now(), nil)
+	select {
+	case s := <-got:
+		if s != "done" {
+			t.Errorf("WaitOpen read %q", s)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitOpen did not return after the key was put")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := d.WaitOpen(ctx, "never"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitOpen = %v, want context.DeadlineExceeded", err)
+	}
+}
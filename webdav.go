@@ -0,0 +1,137 @@
+package gomemfs
+
+import (
+	"encoding/xml"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// WebDAVHandler returns an [http.Handler] exposing the keys of d over WebDAV,
+// read-only, so that they can be browsed or mounted with the WebDAV clients of
+// operating systems and tools. Requests for paths not beginning with prefix
+// are answered with 404, and prefix is removed to form the name of a key. GET
+// and HEAD are served as by FileServer; PROPFIND describes keys and lists
+// directories to a depth of 1, always reporting every property; methods that
+// would modify the FS are refused.
+//
+// Directory listings show the keys held by the FS, as FS.ReadDir does, so a
+// key that has not been fulfilled yet is not listed, though it can be opened.
+func WebDAVHandler(d *FS, prefix string) http.Handler {
+	files := FileServer(d)
+	prefix = strings.TrimSuffix(prefix, "/")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, prefix)
+		if !ok || (rest != "" && rest[0] != '/') {
+			http.NotFound(w, r)
+			return
+		}
+		name := strings.Trim(rest, "/")
+		if name == "" {
+			name = "."
+		}
+
+		switch r.Method {
+		case http.MethodOptions:
+			w.Header().Set("DAV", "1")
+			w.Header().Set("Allow", "OPTIONS, GET, HEAD, PROPFIND")
+		case http.MethodGet, http.MethodHead:
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = &url.URL{Path: "/" + name, RawQuery: r.URL.RawQuery}
+			files.ServeHTTP(w, r2)
+		case "PROPFIND":
+			d.propfind(w, r, prefix, name)
+		case http.MethodPut, http.MethodDelete, "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK":
+			http.Error(w, "read-only", http.StatusForbidden)
+		default:
+			w.Header().Set("Allow", "OPTIONS, GET, HEAD, PROPFIND")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// davResponse is one response element of a PROPFIND multistatus reply.
+type davResponse struct {
+	XMLName xml.Name `xml:"D:response"`
+	Href    string   `xml:"D:href"`
+	Prop    davProp  `xml:"D:propstat>D:prop"`
+	Status  string   `xml:"D:propstat>D:status"`
+}
+
+type davProp struct {
+	DisplayName   string    `xml:"D:displayname"`
+	ResourceType  *struct{} `xml:"D:resourcetype>D:collection,omitempty"`
+	ContentLength *int64    `xml:"D:getcontentlength,omitempty"`
+	ContentType   string    `xml:"D:getcontenttype,omitempty"`
+	LastModified  string    `xml:"D:getlastmodified,omitempty"`
+}
+
+func (d *FS) propfind(w http.ResponseWriter, r *http.Request, prefix, name string) {
+	depth := r.Header.Get("Depth")
+	if depth == "" || strings.EqualFold(depth, "infinity") {
+		// RFC 4918 allows servers to refuse to walk the whole tree
+		http.Error(w, "Depth must be 0 or 1", http.StatusForbidden)
+		return
+	}
+	fi, err := d.Stat(name)
+	if err != nil {
+		httpError(w, err)
+		return
+	}
+	resps := []davResponse{davEntry(prefix, name, fi)}
+	if fi.IsDir() && depth == "1" {
+		des, err := d.ReadDir(name)
+		if err != nil {
+			httpError(w, err)
+			return
+		}
+		for _, de := range des {
+			fi, err := de.Info()
+			if err != nil {
+				continue
+			}
+			resps = append(resps, davEntry(prefix, path.Join(name, de.Name()), fi))
+		}
+	}
+
+	w.Header().Set("Content-Type", `application/xml; charset="utf-8"`)
+	w.WriteHeader(http.StatusMultiStatus)
+	w.Write([]byte(xml.Header + `<D:multistatus xmlns:D="DAV:">`))
+	enc := xml.NewEncoder(w)
+	for _, resp := range resps {
+		enc.Encode(resp)
+	}
+	enc.Flush()
+	w.Write([]byte(`</D:multistatus>`))
+}
+
+// davEntry describes the key or directory name for a PROPFIND reply.
+func davEntry(prefix, name string, fi fs.FileInfo) davResponse {
+	href := prefix + "/"
+	if name != "." {
+		href += (&url.URL{Path: name}).EscapedPath()
+	}
+	resp := davResponse{
+		Href:   href,
+		Status: "HTTP/1.1 200 OK",
+		Prop: davProp{
+			DisplayName:  fi.Name(),
+			LastModified: fi.ModTime().UTC().Format(http.TimeFormat),
+		},
+	}
+	if fi.IsDir() {
+		if name != "." {
+			resp.Href += "/"
+		}
+		resp.Prop.ResourceType = &struct{}{}
+	} else {
+		size := fi.Size()
+		resp.Prop.ContentLength = &size
+		resp.Prop.ContentType = mime.TypeByExtension(path.Ext(name))
+	}
+	return resp
+}
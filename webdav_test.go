@@ -0,0 +1,88 @@
+package gomemfs
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// propfind sends a PROPFIND request to h and decodes the hrefs of the reply.
+func propfind(t *testing.T, h http.Handler, target, depth string) (int, []string) {
+	t.Helper()
+	r := httptest.NewRequest("PROPFIND", target, nil)
+	if depth != "" {
+		r.Header.Set("Depth", depth)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusMultiStatus {
+		return w.Code, nil
+	}
+	var ms struct {
+		Responses []struct {
+			Href string `xml:"href"`
+		} `xml:"DAV: response"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &ms); err != nil {
+		t.Fatal(err)
+	}
+	var hrefs []string
+	for _, resp := range ms.Responses {
+		hrefs = append(hrefs, resp.Href)
+	}
+	return w.Code, hrefs
+}
+
+func TestWebDAVHandler(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	d.Put("docs/a b.txt", []byte("hello"), now, nil)
+	d.Put("docs/sub/c.txt", []byte("c"), now, nil)
+	h := WebDAVHandler(d, "/dav/")
+
+	code, hrefs := propfind(t, h, "/dav/docs", "1")
+	want := []string{"/dav/docs/", "/dav/docs/a%20b.txt", "/dav/docs/sub/"}
+	if code != http.StatusMultiStatus || len(hrefs) != len(want) {
+		t.Fatalf("PROPFIND = %d %q, want %q", code, hrefs, want)
+	}
+	for i := range want {
+		if hrefs[i] != want[i] {
+			t.Errorf("href %d = %q, want %q", i, hrefs[i], want[i])
+		}
+	}
+	if _, hrefs := propfind(t, h, "/dav/", "0"); len(hrefs) != 1 || hrefs[0] != "/dav/" {
+		t.Errorf("PROPFIND of the root = %q", hrefs)
+	}
+	if code, _ := propfind(t, h, "/dav/docs", "infinity"); code != http.StatusForbidden {
+		t.Errorf("PROPFIND with Depth infinity = %d, want 403", code)
+	}
+	if code, _ := propfind(t, h, "/dav/missing", "0"); code != http.StatusNotFound {
+		t.Errorf("PROPFIND of a missing key = %d, want 404", code)
+	}
+
+	if w := get(h, "/dav/docs/a%20b.txt"); w.Code != http.StatusOK || w.Body.String() != "hello" {
+		t.Errorf("GET = %d %q", w.Code, w.Body)
+	}
+	for target, code := range map[string]int{"/other/x": http.StatusNotFound, "/davx": http.StatusNotFound} {
+		if w := get(h, target); w.Code != code {
+			t.Errorf("GET %s = %d, want %d", target, w.Code, code)
+		}
+	}
+	for method, code := range map[string]int{
+		http.MethodPut:     http.StatusForbidden,
+		"MKCOL":            http.StatusForbidden,
+		http.MethodPost:    http.StatusMethodNotAllowed,
+		http.MethodOptions: http.StatusOK,
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/dav/docs/a%20b.txt", nil))
+		if w.Code != code {
+			t.Errorf("%s = %d, want %d", method, w.Code, code)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package gomemfs
+
+// WipeContent, if true, causes the content of a key to be overwritten with
+// zeros once the FS no longer holds it, because it expired or was removed or
+// replaced (or, with KeepVersions, once it is no longer kept as a previous
+// version), so that secrets do not linger in memory. Content is wiped when
+// the FS is next unlocked, after any OnExpire or OnEvict callbacks have run.
+// Each key then holds a private copy of its content, rather than eg the buffer
+// passed to Put, and readers such as open Files and any Clone, Merge or At
+// view of the FS are given their own copies, so that wiping never affects
+// them. Keys stored before WipeContent was set are not wiped, nor are files
+// mapped with PutMapped, which are read-only.
+type WipeContent bool
+
+func (fso WipeContent) applyTo(fs *FS) error {
+	fs.wipe = bool(fso)
+	return nil
+}
+
+// discard queues the content of k, which the FS no longer holds, to be wiped
+// if WipeContent is set.
+func (d *FS) discard(k *key) {
+	// must be called with fs.mu Locked
+	if !k.wipe || len(k.bytes) == 0 {
+		return
+	}
+	d.wipes = append(d.wipes, k.bytes)
+}
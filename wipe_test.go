@@ -0,0 +1,68 @@
+package gomemfs
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWipeContent(t *testing.T) {
+	d, err := New(WipeContent(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := []byte("secret")
+	d.Put("k", buf, time.Now(), nil)
+	d.mu.Lock()
+	held := d.lookup("k")
+	d.unlock()
+
+	c := d.Clone()
+	f, err := d.Open("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := d.Expire("k"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(held.bytes, make([]byte, len("secret"))) {
+		t.Errorf("retired content = %q, want zeros", held.bytes)
+	}
+	if string(buf) != "secret" {
+		t.Errorf("buffer passed to Put = %q, want it untouched", buf)
+	}
+	if b, err := io.ReadAll(f); err != nil || string(b) != "secret" {
+		t.Errorf("open File read %q, %v", b, err)
+	}
+	if b, err := c.ReadFile("k"); err != nil || string(b) != "secret" {
+		t.Errorf("Clone read %q, %v", b, err)
+	}
+}
+
+func TestWipeContentSharedByClone(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("k", []byte("secret"), time.Now(), nil)
+	// a clone that wipes must not wipe the buffer still held by d
+	c := d.Clone()
+	if err := c.Set(WipeContent(true)); err != nil {
+		t.Fatal(err)
+	}
+	m, err := New(WipeContent(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Merge(d, false); err != nil {
+		t.Fatal(err)
+	}
+	m.Expire("k")
+	c.Expire("k")
+	if b, err := d.ReadFile("k"); err != nil || string(b) != "secret" {
+		t.Errorf("ReadFile = %q, %v", b, err)
+	}
+}
@@ -0,0 +1,120 @@
+package gomemfs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+)
+
+// WriteDir writes every key currently held by the FS to a file beneath the
+// directory root, creating root and any parent directories as needed and
+// setting each file's mode and modtime. Existing files are overwritten.
+// Nothing is fulfilled. Keys whose names cannot be represented as a local path
+// beneath root, such as ones containing "..", cause an error. Symbolic links
+// are written as symbolic links with a target relative to the link; a link
+// whose target is outside root, or a key beneath a link, causes an error, so
+// that files are only ever written beneath root.
+func (d *FS) WriteDir(root string) error {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return fmt.Errorf("cannot create %q: %w", root, err)
+	}
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		return fmt.Errorf("cannot open %q: %w", root, err)
+	}
+	defer r.Close()
+	keys := d.snapshot("")
+	links := make(map[string]bool)
+	for _, k := range keys {
+		if k.link != "" {
+			links[k.name] = true
+		}
+	}
+	for _, k := range keys {
+		for dir := path.Dir(k.name); dir != "."; dir = path.Dir(dir) {
+			if links[dir] {
+				return fmt.Errorf("cannot write key %q beneath symbolic link %q", k.name, dir)
+			}
+		}
+		rel, err := filepath.Localize(k.name)
+		if err != nil {
+			return fmt.Errorf("cannot write key %q beneath %q: %w", k.name, root, err)
+		}
+		if err := mkdirAllIn(r, filepath.Dir(rel)); err != nil {
+			return fmt.Errorf("cannot create directory for key %q: %w", k.name, err)
+		}
+		if k.link != "" {
+			t := k.relTarget()
+			if !filepath.IsLocal(filepath.FromSlash(path.Join(path.Dir(k.name), t))) {
+				return fmt.Errorf("cannot write link %q: target %q is outside %q", k.name, k.link, root)
+			}
+			if err := writeLink(r, rel, filepath.FromSlash(t)); err != nil {
+				return fmt.Errorf("cannot write link %q: %w", k.name, err)
+			}
+			continue
+		}
+		b, err := k.content()
+		if err != nil {
+			return fmt.Errorf("cannot write key: %w", err)
+		}
+		if err := writeFileIn(r, rel, b, k.exportMode()); err != nil {
+			return fmt.Errorf("cannot write key %q: %w", k.name, err)
+		}
+		// the file was just opened through r, so no link leads outside root
+		if err := os.Chtimes(filepath.Join(root, rel), k.modtime, k.modtime); err != nil {
+			return fmt.Errorf("cannot set modtime of key %q: %w", k.name, err)
+		}
+	}
+	return nil
+}
+
+// mkdirAllIn creates the directory dir beneath r along with any parents.
+func mkdirAllIn(r *os.Root, dir string) error {
+	if dir == "." {
+		return nil
+	}
+	if fi, err := r.Stat(dir); err == nil {
+		if !fi.IsDir() {
+			return &fs.PathError{Op: "mkdir", Path: dir, Err: syscall.ENOTDIR}
+		}
+		return nil
+	}
+	if err := mkdirAllIn(r, filepath.Dir(dir)); err != nil {
+		return err
+	}
+	if err := r.Mkdir(dir, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+// writeFileIn writes b to the file name beneath r with the given mode,
+// replacing any existing file.
+func writeFileIn(r *os.Root, name string, b []byte, mode fs.FileMode) error {
+	f, err := r.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Chmod(mode)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// writeLink creates a symbolic link called name beneath r, replacing any
+// existing file.
+func writeLink(r *os.Root, name, target string) error {
+	if err := r.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	// the parent was just created or found through r, so is beneath it
+	return os.Symlink(target, filepath.Join(r.Name(), name))
+}
@@ -0,0 +1,63 @@
+package gomemfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteDir(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Put("b/c.txt", []byte("hi"), time.Now(), nil)
+	d.Symlink("/b/c.txt", "l/link")
+	root := filepath.Join(t.TempDir(), "root")
+	if err := d.WriteDir(root); err != nil {
+		t.Fatal(err)
+	}
+	// writing again overwrites what is there
+	if err := d.WriteDir(root); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(filepath.Join(root, "l", "link")); err != nil || string(b) != "hi" {
+		t.Errorf("read through link = %q, %v", b, err)
+	}
+	if target, err := os.Readlink(filepath.Join(root, "l", "link")); err != nil || target != filepath.FromSlash("../b/c.txt") {
+		t.Errorf("link target = %q, %v", target, err)
+	}
+}
+
+func TestWriteDirStaysInRoot(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		put  func(d *FS)
+	}{
+		{"link to parent", func(d *FS) {
+			d.Symlink("..", "a")
+			d.Put("a/escaped.txt", []byte("x"), time.Now(), nil)
+		}},
+		{"relative link", func(d *FS) { d.Symlink("../../escaped.txt", "q/l") }},
+		{"key beneath link", func(d *FS) {
+			d.Symlink("/sub", "a")
+			d.Put("a/escaped.txt", []byte("x"), time.Now(), nil)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			tc.put(d)
+			tmp := t.TempDir()
+			if err := d.WriteDir(filepath.Join(tmp, "root")); err == nil {
+				t.Error("WriteDir succeeded")
+			}
+			if _, err := os.Lstat(filepath.Join(tmp, "escaped.txt")); err == nil {
+				t.Error("file written outside root")
+			}
+		})
+	}
+}
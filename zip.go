@@ -0,0 +1,47 @@
+package gomemfs
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// WriteZip writes every key currently held by the FS to w as a zip archive,
+// with the key's name, mode and modtime. Keys are written in order of name and
+// nothing is fulfilled. If compress is nil every entry is deflated; otherwise
+// an entry is only deflated if compress returns true for its name, which
+// allows already compressed content such as images to be stored as-is. The
+// archive is finished but w is not closed.
+func (d *FS) WriteZip(w io.Writer, compress func(name string) bool) error {
+	zw := zip.NewWriter(w)
+	for _, k := range d.snapshot("") {
+		hdr := &zip.FileHeader{
+			Name:     k.name,
+			Method:   zip.Deflate,
+			Modified: k.modtime,
+		}
+		if compress != nil && !compress(k.name) {
+			hdr.Method = zip.Store
+		}
+		content, err := k.content()
+		if err != nil {
+			return fmt.Errorf("cannot write zip content: %w", err)
+		}
+		hdr.SetMode(k.exportMode())
+		if k.link != "" {
+			// by convention, the content of a zip symlink is its target
+			content = []byte(k.relTarget())
+			hdr.Method = zip.Store
+			hdr.SetMode(fs.ModeSymlink | 0o777)
+		}
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return fmt.Errorf("cannot write zip header for %q: %w", k.name, err)
+		}
+		if _, err := fw.Write(content); err != nil {
+			return fmt.Errorf("cannot write zip content for %q: %w", k.name, err)
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,47 @@
+package gomemfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"path"
+	"testing"
+	"time"
+)
+
+func TestWriteZip(t *testing.T) {
+	d, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.Put("b/page.html", []byte("<html>"), mt, nil, Mode(0o600))
+	d.Put("a.png", []byte("png"), mt, nil)
+	d.Symlink("b/page.html", "latest")
+
+	var buf bytes.Buffer
+	err = d.WriteZip(&buf, func(name string) bool { return path.Ext(name) != ".png" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 3 {
+		t.Fatalf("%d entries, want 3", len(zr.File))
+	}
+	png, page, link := zr.File[0], zr.File[1], zr.File[2]
+	if png.Name != "a.png" || png.Method != zip.Store {
+		t.Errorf("a.png: %q method %d", png.Name, png.Method)
+	}
+	if page.Method != zip.Deflate || page.Mode().Perm() != 0o600 || !page.Modified.Equal(mt) {
+		t.Errorf("b/page.html: method %d mode %v modtime %v", page.Method, page.Mode(), page.Modified)
+	}
+	if link.Mode()&fs.ModeSymlink == 0 {
+		t.Errorf("latest: mode %v, want a symlink", link.Mode())
+	}
+	if b, err := fs.ReadFile(zr, "latest"); err != nil || string(b) != "b/page.html" {
+		t.Errorf("latest = %q, %v", b, err)
+	}
+}